@@ -0,0 +1,273 @@
+package add
+
+import (
+	"context"
+	"fmt"
+
+	initcmd "github.com/cloudquery/cloudquery/cmd/init"
+	"github.com/cloudquery/cloudquery/cmd/utils"
+	"github.com/cloudquery/cloudquery/pkg/config"
+	"github.com/cloudquery/cloudquery/pkg/core"
+	"github.com/cloudquery/cloudquery/pkg/plugin/registry"
+	"github.com/cloudquery/cloudquery/pkg/ui"
+	"github.com/cloudquery/cloudquery/pkg/ui/console"
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	addShort   = "Add one or more providers to an existing cloudquery.yml"
+	addExample = `
+  # Adds the gcp provider to the existing cloudquery.yml
+  cloudquery add gcp
+
+  # Adds aws and okta providers, writing the result to a separate file for review
+  cloudquery add aws okta --out cloudquery.new.yml`
+)
+
+func NewCmdAdd() *cobra.Command {
+	addCmd := &cobra.Command{
+		Use:     "add [choose one or more providers (aws gcp azure okta ...)]",
+		Short:   addShort,
+		Long:    addShort,
+		Example: addExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    add,
+	}
+	addCmd.Flags().String("out", "", "write the updated configuration to this file instead of overwriting the existing config")
+	addCmd.Flags().String("plugin-mirror", "", "filesystem or HTTPS mirror to resolve provider sources against instead of the default registry (also settable via CLOUDQUERY_PROVIDER_MIRROR)")
+	addCmd.Flags().Bool("upgrade", false, "ignore cloudquery.lock.yaml and re-resolve version constraints against the newest available provider releases")
+	addCmd.Flags().StringArray("var", nil, "set a value for a declared variable, as name=value (can be repeated)")
+	addCmd.Flags().StringArray("var-file", nil, "load variable values from a file, yaml or tfvars-style name = value (can be repeated)")
+	return addCmd
+}
+
+func add(cmd *cobra.Command, providers []string) error {
+	fs := afero.NewOsFs()
+	ctx := cmd.Context()
+
+	configPath := utils.GetConfigFile()
+
+	info, _ := fs.Stat(configPath)
+	if info == nil {
+		ui.ColorizedOutput(ui.ColorError, "Error: Config file %s does not exist, use `cloudquery init` instead\n", configPath)
+		cmd.SilenceErrors = true
+		return diag.FromError(fmt.Errorf("config file %q does not exist", configPath), diag.USER)
+	}
+
+	existing, diags := (&config.Parser{}).LoadConfigFile(configPath)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	existingBytes, err := afero.ReadFile(fs, configPath)
+	if err != nil {
+		return diag.FromError(err, diag.USER, diag.WithSummary("Failed to read existing config"))
+	}
+	sourceOverrides, diags := config.ExtractProviderSourceOverrides(existingBytes, config.FormatFromExtension(configPath))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	requiredProviders := make([]*config.RequiredProvider, 0, len(providers))
+	seenRequired := make(map[string]bool, len(providers)+len(existing.CloudQuery.Providers))
+	for _, rp := range existing.CloudQuery.Providers {
+		seenRequired[rp.Name+"@"+rp.Version] = true
+	}
+	blocks := make([]initcmd.ProviderBlock, len(providers))
+	for i, p := range providers {
+		organization, providerName, provVersion, alias, err := initcmd.ParseProviderCLIArg(p)
+		if err != nil {
+			return fmt.Errorf("could not parse requested provider: %w", err)
+		}
+		requiredKey := providerName + "@" + provVersion
+		if !seenRequired[requiredKey] {
+			rp := config.RequiredProvider{
+				Name:    providerName,
+				Version: provVersion,
+			}
+			defaultSource := ""
+			if organization != registry.DefaultOrganization {
+				defaultSource = fmt.Sprintf("%s/%s", organization, providerName)
+			}
+			if source := config.ResolveProviderSource(sourceOverrides, providerName, defaultSource); source != "" {
+				rp.Source = &source
+			}
+			requiredProviders = append(requiredProviders, &rp)
+			seenRequired[requiredKey] = true
+		}
+		blocks[i] = initcmd.ProviderBlock{Name: providerName, Alias: alias}
+	}
+
+	if err := validateNoDuplicateProviders(existing, blocks); err != nil {
+		return err
+	}
+
+	mergedConfig := *existing
+	mergedConfig.CloudQuery.Providers = append(append([]*config.RequiredProvider{}, existing.CloudQuery.Providers...), requiredProviders...)
+	if diags := config.ProcessConfig(&mergedConfig); diags.HasErrors() {
+		return diags
+	}
+
+	cCfg := mergedConfig
+	cCfg.CloudQuery.Connection.DSN = "" // Don't connect
+	c, err := console.CreateClientFromConfig(ctx, &cCfg, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.DownloadProviders(ctx); err != nil {
+		return err
+	}
+	if diags := config.LockResolvedProviders(ctx, &mergedConfig, configPath); diags.HasErrors() {
+		return diags
+	}
+
+	b, err := generateAppendedConfig(ctx, c, configPath, blocks, requiredProviders)
+	if err != nil {
+		return err
+	}
+
+	outPath := configPath
+	if out, _ := cmd.Flags().GetString("out"); out != "" {
+		outPath = out
+	}
+	if err := afero.WriteFile(fs, outPath, b, 0644); err != nil {
+		return diag.FromError(err, diag.USER, diag.WithSummary("Failed to write configuration"))
+	}
+	ui.ColorizedOutput(ui.ColorSuccess, "configuration updated successfully at %s\n", outPath)
+	return nil
+}
+
+// validateNoDuplicateProviders applies the same name/alias uniqueness rules as
+// config.validateProvidersBlock against the `providers:` blocks already present in the
+// existing config: a provider name can only be reused if the new block has an alias that
+// doesn't collide with an existing name or alias.
+func validateNoDuplicateProviders(existing *config.Config, added []initcmd.ProviderBlock) error {
+	seen := make(map[string]bool, len(existing.Providers))
+	for _, p := range existing.Providers {
+		if p.Alias != "" {
+			seen[p.Alias] = true
+		} else {
+			seen[p.Name] = true
+		}
+	}
+	for _, b := range added {
+		key := b.Alias
+		if key == "" {
+			key = b.Name
+		}
+		if seen[key] {
+			return diag.FromError(fmt.Errorf("provider %s already exists in %s, use an alias (`%s@alias=<name>`) to add another configuration of it", b.Name, utils.GetConfigFile(), b.Name), diag.USER, diag.WithSummary("Duplicate Provider"))
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// generateAppendedConfig reads the existing config file as a yaml.Node tree and splices in the
+// newly fetched provider blocks, preserving the existing comments and ordering.
+func generateAppendedConfig(ctx context.Context, c *console.Client, configPath string, blocks []initcmd.ProviderBlock, required []*config.RequiredProvider) ([]byte, error) {
+	fs := afero.NewOsFs()
+	existingBytes, err := afero.ReadFile(fs, configPath)
+	if err != nil {
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to read existing config"))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(existingBytes, &root); err != nil {
+		return nil, diag.WrapError(err)
+	}
+	if len(root.Content) == 0 {
+		return nil, diag.FromError(fmt.Errorf("existing config %q is empty", configPath), diag.USER)
+	}
+	doc := root.Content[0]
+
+	providersNode := findOrCreateProvidersNode(doc)
+	cloudqueryNode := findMappingValue(doc, "cloudquery")
+	if cloudqueryNode != nil {
+		appendRequiredProviders(cloudqueryNode, required)
+	}
+
+	for _, b := range blocks {
+		pCfg, diags := core.GetProviderConfiguration(ctx, c.PluginManager, &core.GetProviderConfigOptions{
+			Provider: c.ConvertRequiredToRegistry(b.Name),
+		})
+		if pCfg != nil && pCfg.Format != 1 /* YAML */ {
+			diags = diags.Add(diag.FromError(fmt.Errorf("provider %s doesn't support YAML config. Please upgrade provider", b.Name), diag.USER))
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		var yCfg yaml.Node
+		if err := yaml.Unmarshal(pCfg.Config, &yCfg); err != nil {
+			return nil, diag.WrapError(err)
+		}
+
+		header := []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "name"},
+			{Kind: yaml.ScalarNode, Value: b.Name},
+		}
+		if b.Alias != "" {
+			header = append(header,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "alias"},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: b.Alias},
+			)
+		}
+
+		providersNode.Content = append(providersNode.Content, &yaml.Node{
+			Kind:    yaml.MappingNode,
+			Content: append(header, yCfg.Content[0].Content...),
+		})
+	}
+
+	return yaml.Marshal(&root)
+}
+
+// findOrCreateProvidersNode locates the top-level `providers:` sequence node, creating it if
+// the existing config doesn't have one yet (e.g. it only declared `cloudquery:`).
+func findOrCreateProvidersNode(doc *yaml.Node) *yaml.Node {
+	if n := findMappingValue(doc, "providers"); n != nil {
+		return n
+	}
+	n := &yaml.Node{Kind: yaml.SequenceNode, HeadComment: "provider configurations"}
+	doc.Content = append(doc.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "providers"},
+		n,
+	)
+	return n
+}
+
+// findMappingValue returns the value node for the given key in a top-level mapping document node.
+func findMappingValue(doc *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == key {
+			return doc.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// appendRequiredProviders splices the newly required providers into the existing
+// `cloudquery.providers` sequence node.
+func appendRequiredProviders(cloudqueryNode *yaml.Node, required []*config.RequiredProvider) {
+	providersSeq := findMappingValue(cloudqueryNode, "providers")
+	if providersSeq == nil {
+		return
+	}
+	for _, rp := range required {
+		b, err := yaml.Marshal(rp)
+		if err != nil {
+			continue
+		}
+		var n yaml.Node
+		if err := yaml.Unmarshal(b, &n); err != nil || len(n.Content) == 0 {
+			continue
+		}
+		providersSeq.Content = append(providersSeq.Content, n.Content[0])
+	}
+}
@@ -0,0 +1,57 @@
+package init
+
+import (
+	"fmt"
+
+	"github.com/cloudquery/cloudquery/pkg/config"
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigEncoder serializes a generated configuration document (a yaml.Node tree with
+// `cloudquery` and `providers` top-level keys) into the bytes that get written to disk.
+// Implementations transcode the same in-memory document, so the set of providers and their
+// configuration is identical regardless of --format.
+type ConfigEncoder interface {
+	// Extension returns the default file extension for this encoder, e.g. ".yml".
+	Extension() string
+	Encode(doc *yaml.Node) ([]byte, error)
+}
+
+type yamlEncoder struct{}
+
+func (yamlEncoder) Extension() string { return ".yml" }
+
+func (yamlEncoder) Encode(doc *yaml.Node) ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Extension() string { return ".json" }
+
+func (jsonEncoder) Encode(doc *yaml.Node) ([]byte, error) {
+	return config.EncodeJSON(doc)
+}
+
+type hclEncoder struct{}
+
+func (hclEncoder) Extension() string { return ".hcl" }
+
+func (hclEncoder) Encode(doc *yaml.Node) ([]byte, error) {
+	return config.EncodeHCL(doc)
+}
+
+// encoderForFormat resolves the --format flag value to a ConfigEncoder.
+func encoderForFormat(format string) (ConfigEncoder, error) {
+	switch config.Format(format) {
+	case config.FormatYAML, "":
+		return yamlEncoder{}, nil
+	case config.FormatJSON:
+		return jsonEncoder{}, nil
+	case config.FormatHCL:
+		return hclEncoder{}, nil
+	default:
+		return nil, diag.FromError(fmt.Errorf("unsupported config format %q, expected one of yaml, json, hcl", format), diag.USER)
+	}
+}
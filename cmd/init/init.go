@@ -26,7 +26,15 @@ const (
   cloudquery init aws
 
   # Downloads aws,gcp providers and generates one cloudquery.yml with both providers
-  cloudquery init aws gcp`
+  cloudquery init aws gcp
+
+  # Generates cloudquery.yml with two aliased aws provider blocks, e.g. for scanning
+  # multiple organizations in a single fetch
+  cloudquery init aws aws@alias=secondary
+
+  # Generates cloudquery.hcl instead of cloudquery.yml, e.g. to keep config next to
+  # Terraform modules
+  cloudquery init --format=hcl aws`
 )
 
 func NewCmdInit() *cobra.Command {
@@ -38,6 +46,10 @@ func NewCmdInit() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 		RunE:    initialize,
 	}
+	initCmd.Flags().String("format", "", "config format to generate: yaml (default), json, or hcl")
+	initCmd.Flags().String("plugin-mirror", "", "filesystem or HTTPS mirror to resolve provider sources against instead of the default registry (also settable via CLOUDQUERY_PROVIDER_MIRROR)")
+	initCmd.Flags().StringArray("var", nil, "set a value for a declared variable, as name=value (can be repeated)")
+	initCmd.Flags().StringArray("var-file", nil, "load variable values from a file, yaml or tfvars-style name = value (can be repeated)")
 	return initCmd
 }
 
@@ -47,6 +59,19 @@ func initialize(cmd *cobra.Command, providers []string) error {
 
 	configPath := utils.GetConfigFile() // by definition, this will get us an existing file if possible
 
+	formatFlag, _ := cmd.Flags().GetString("format")
+	encoder, err := encoderForFormat(formatFlag)
+	if err != nil {
+		return err
+	}
+	if formatFlag != "" {
+		// An explicit --format overrides whatever extension GetConfigFile() assumed.
+		configPath = strings.TrimSuffix(configPath, filepath.Ext(configPath)) + encoder.Extension()
+	} else if strings.ToLower(filepath.Ext(configPath)) == ".hcl" {
+		ui.ColorizedOutput(ui.ColorError, "Error: HCL config format is deprecated and should not be used for new installations\n")
+		return diag.FromError(fmt.Errorf("deprecated format %q", configPath), diag.USER)
+	}
+
 	if info, _ := fs.Stat(configPath); info != nil {
 		ui.ColorizedOutput(ui.ColorError, "Error: Config file %s already exists\n", configPath)
 		// We don't want to print the error twice, so we set the `SilenceErrors` flag to true
@@ -54,27 +79,41 @@ func initialize(cmd *cobra.Command, providers []string) error {
 		return diag.FromError(fmt.Errorf("config file %q already exists", configPath), diag.USER)
 	}
 
-	if strings.ToLower(filepath.Ext(configPath)) == ".hcl" {
-		ui.ColorizedOutput(ui.ColorError, "Error: HCL config format is deprecated and should not be used for new installations\n")
-		return diag.FromError(fmt.Errorf("deprecated format %q", configPath), diag.USER)
-	}
-
-	requiredProviders := make([]*config.RequiredProvider, len(providers))
+	requiredProviders := make([]*config.RequiredProvider, 0, len(providers))
+	seenRequired := make(map[string]bool, len(providers))
+	blocks := make([]ProviderBlock, len(providers))
 	for i, p := range providers {
-		organization, providerName, provVersion, err := ParseProviderCLIArg(p)
+		organization, providerName, provVersion, alias, err := ParseProviderCLIArg(p)
 		if err != nil {
 			return fmt.Errorf("could not parse requested provider: %w", err)
 		}
-		rp := config.RequiredProvider{
-			Name:    providerName,
-			Version: provVersion,
-		}
-		if organization != registry.DefaultOrganization {
-			source := fmt.Sprintf("%s/%s", organization, providerName)
-			rp.Source = &source
+		// A provider required more than once (one per alias) only needs a single
+		// `cloudquery.providers` download entry; the alias only affects the
+		// generated `providers:` configuration block below.
+		requiredKey := providerName + "@" + provVersion
+		if !seenRequired[requiredKey] {
+			rp := config.RequiredProvider{
+				Name:    providerName,
+				Version: provVersion,
+			}
+			defaultSource := ""
+			if organization != registry.DefaultOrganization {
+				defaultSource = fmt.Sprintf("%s/%s", organization, providerName)
+			}
+			// A plugin mirror or a provider_source_overrides entry (the latter can only come
+			// from an existing config, so it doesn't apply to a fresh `init`) takes precedence
+			// over the org/name-derived source above.
+			if source := config.ResolveProviderSource(nil, providerName, defaultSource); source != "" {
+				rp.Source = &source
+			}
+			requiredProviders = append(requiredProviders, &rp)
+			seenRequired[requiredKey] = true
 		}
-		requiredProviders[i] = &rp
-		providers[i] = providerName // overwrite "provider@version" with just "provider"
+		blocks[i] = ProviderBlock{Name: providerName, Alias: alias}
+	}
+
+	if err := validateNoDuplicateProviders(blocks); err != nil {
+		return err
 	}
 
 	mainConfig := config.Config{
@@ -104,8 +143,15 @@ func initialize(cmd *cobra.Command, providers []string) error {
 	if err := c.DownloadProviders(ctx); err != nil {
 		return err
 	}
+	if diags := config.LockResolvedProviders(ctx, &mainConfig, configPath); diags.HasErrors() {
+		return diags
+	}
 
-	b, err := generateConfig(ctx, c, providers, mainConfig)
+	doc, err := generateConfig(ctx, c, blocks, mainConfig)
+	if err != nil {
+		return err
+	}
+	b, err := encoder.Encode(doc)
 	if err != nil {
 		return err
 	}
@@ -114,7 +160,38 @@ func initialize(cmd *cobra.Command, providers []string) error {
 	return nil
 }
 
-func generateConfig(ctx context.Context, c *console.Client, providers []string, mainConfig config.Config) ([]byte, error) {
+// ProviderBlock identifies a single `providers:` configuration block to generate: a provider
+// name plus an optional alias distinguishing it from other blocks for the same provider.
+type ProviderBlock struct {
+	Name  string
+	Alias string
+}
+
+// validateNoDuplicateProviders applies the same name/alias uniqueness rules as
+// config.validateProvidersBlock against the provider blocks `init` is about to generate: a
+// provider name can only be requested more than once if every extra occurrence has an alias
+// that doesn't collide with another name or alias. These blocks don't exist as config.Provider
+// values yet at ProcessConfig time (they're spliced into the document afterward by
+// generateConfig), so validateProvidersBlock never sees them; this mirrors
+// cmd/add.validateNoDuplicateProviders, which catches the same mistake for `add`.
+func validateNoDuplicateProviders(blocks []ProviderBlock) error {
+	seen := make(map[string]bool, len(blocks))
+	for _, b := range blocks {
+		key := b.Alias
+		if key == "" {
+			key = b.Name
+		}
+		if seen[key] {
+			return diag.FromError(fmt.Errorf("provider %s already requested, use an alias (`%s@alias=<name>`) to request another configuration of it", b.Name, b.Name), diag.USER, diag.WithSummary("Duplicate Provider"))
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// generateConfig builds the full configuration document as a yaml.Node tree (rather than bytes
+// directly) so callers can hand it to any ConfigEncoder, e.g. to emit JSON or HCL instead of YAML.
+func generateConfig(ctx context.Context, c *console.Client, blocks []ProviderBlock, mainConfig config.Config) (*yaml.Node, error) {
 	cqConfig := struct {
 		CloudQuery config.CloudQuery `yaml:"cloudquery" json:"cloudquery"`
 	}{
@@ -137,12 +214,12 @@ func generateConfig(ctx context.Context, c *console.Client, providers []string,
 		HeadComment: "provider configurations",
 	}
 
-	for _, p := range providers {
+	for _, b := range blocks {
 		pCfg, diags := core.GetProviderConfiguration(ctx, c.PluginManager, &core.GetProviderConfigOptions{
-			Provider: c.ConvertRequiredToRegistry(p),
+			Provider: c.ConvertRequiredToRegistry(b.Name),
 		})
 		if pCfg != nil && pCfg.Format != 1 /* YAML */ {
-			diags = diags.Add(diag.FromError(fmt.Errorf("provider %s doesn't support YAML config. Please upgrade provider", p), diag.USER))
+			diags = diags.Add(diag.FromError(fmt.Errorf("provider %s doesn't support YAML config. Please upgrade provider", b.Name), diag.USER))
 		}
 		if diags.HasErrors() {
 			return nil, diags
@@ -153,65 +230,75 @@ func generateConfig(ctx context.Context, c *console.Client, providers []string,
 			return nil, diag.WrapError(err)
 		}
 
+		header := []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "name"},
+			{Kind: yaml.ScalarNode, Value: b.Name},
+		}
+		if b.Alias != "" {
+			header = append(header,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "alias"},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: b.Alias},
+			)
+		}
+
 		provNode.Content = append(provNode.Content, &yaml.Node{
-			Kind: yaml.MappingNode,
-			Content: append([]*yaml.Node{
-				{
-					Kind:  yaml.ScalarNode,
-					Value: "name",
-				},
-				{
-					Kind:  yaml.ScalarNode,
-					Value: p,
-				},
-			}, yCfg.Content[0].Content...),
+			Kind:    yaml.MappingNode,
+			Content: append(header, yCfg.Content[0].Content...),
 		})
 	}
 
-	nd := struct {
-		Data map[string]*yaml.Node `yaml:",inline"`
-	}{
-		Data: map[string]*yaml.Node{
-			"cloudquery": &cqConfigRaw.CQ,
-			"providers":  provNode,
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "cloudquery"},
+			&cqConfigRaw.CQ,
+			{Kind: yaml.ScalarNode, Value: "providers"},
+			provNode,
 		},
-	}
-
-	return yaml.Marshal(&nd)
+	}, nil
 }
 
-func ParseProviderCLIArg(providerCLIArg string) (org string, name string, version string, err error) {
-	argParts := strings.Split(providerCLIArg, "@")
-
-	l := len(argParts)
-
-	// e.g. aws@latest@0.1.0
-	if l > 2 {
-		return "", "", "", fmt.Errorf("invalid provider name@version %q", providerCLIArg)
-	}
+const aliasArgPrefix = "alias="
 
-	// e.g. aws@latest
-	if l == 2 && argParts[1] == "latest" {
-		org, name, err = registry.ParseProviderName(argParts[0])
-		return org, name, "latest", err
-	}
+// ParseProviderCLIArg parses a `provider[@version][@alias=name]` CLI argument, e.g.
+// "aws", "aws@0.12.0", "aws@alias=secondary" or "aws@0.12.0@alias=secondary". The alias
+// suffix can appear in either order relative to the version and mirrors Terraform's
+// provider aliasing: it lets the same provider be required more than once, each instance
+// getting its own configuration block.
+func ParseProviderCLIArg(providerCLIArg string) (org string, name string, version string, alias string, err error) {
+	argParts := strings.Split(providerCLIArg, "@")
 
-	// e.g. aws
-	if l == 1 {
-		org, name, err = registry.ParseProviderName(argParts[0])
-		return org, name, "latest", err
+	var versionPart string
+	for _, part := range argParts[1:] {
+		if strings.HasPrefix(part, aliasArgPrefix) {
+			if alias != "" {
+				return "", "", "", "", fmt.Errorf("invalid provider argument %q: alias specified more than once", providerCLIArg)
+			}
+			alias = strings.TrimPrefix(part, aliasArgPrefix)
+			if alias == "" {
+				return "", "", "", "", fmt.Errorf("invalid provider argument %q: alias cannot be empty", providerCLIArg)
+			}
+			continue
+		}
+		if versionPart != "" {
+			return "", "", "", "", fmt.Errorf("invalid provider name@version %q", providerCLIArg)
+		}
+		versionPart = part
 	}
 
-	// e.g. aws@0.12.0
 	org, name, err = registry.ParseProviderName(argParts[0])
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", "", err
 	}
 
-	ver, err := config.ParseVersion(argParts[1])
-	if err != nil {
-		return "", "", "", fmt.Errorf("invalid version %q: %w", argParts[1], err)
+	switch {
+	case versionPart == "" || versionPart == "latest":
+		return org, name, "latest", alias, nil
+	default:
+		ver, err := config.ParseVersion(versionPart)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("invalid version %q: %w", versionPart, err)
+		}
+		return org, name, config.FormatVersion(ver), alias, nil
 	}
-
-	return org, name, config.FormatVersion(ver), nil
 }
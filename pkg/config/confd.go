@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// confDirNames are sibling directory names LoadConfigFile checks, in priority order, for
+// additional config files to merge into the root cloudquery.yml. This lets teams keep
+// per-provider configs in separate files checked in by different owners.
+var confDirNames = []string{"cloudquery.d", "conf.d"}
+
+// mergeConfD looks for a conf.d-style sibling directory next to the root config file and, if
+// found, merges each *.yml/*.yaml file inside it into the root document at the yaml.Node level
+// (preserving comments), returning the merged bytes. If no such directory exists, mainData is
+// returned unchanged.
+func mergeConfD(rootPath string, mainData []byte) ([]byte, diag.Diagnostics) {
+	confDir := findConfDDir(rootPath)
+	if confDir == "" {
+		return mainData, nil
+	}
+
+	matches, err := confDFiles(confDir)
+	if err != nil {
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to list %s", confDir))
+	}
+	if len(matches) == 0 {
+		return mainData, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(mainData, &root); err != nil {
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse %s", rootPath))
+	}
+	if len(root.Content) == 0 {
+		return mainData, nil
+	}
+	doc := root.Content[0]
+
+	for _, match := range matches {
+		b, err := ioutil.ReadFile(match)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to read %s", match))
+		}
+		var part yaml.Node
+		if err := yaml.Unmarshal(b, &part); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse %s", match))
+		}
+		if len(part.Content) == 0 {
+			continue
+		}
+		if diags := mergeConfigDoc(doc, part.Content[0], match); diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	merged, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to re-marshal merged config"))
+	}
+	return merged, nil
+}
+
+func findConfDDir(rootPath string) string {
+	base := filepath.Dir(rootPath)
+	for _, name := range confDirNames {
+		dir := filepath.Join(base, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+func confDFiles(dir string) ([]string, error) {
+	var matches []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		m, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m...)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// mergeConfigDoc merges a cloudquery.d part document into the root document:
+//   - cloudquery.providers (the required-provider list) is concatenated and de-duplicated by name+version
+//   - the top-level providers list is concatenated (name/alias uniqueness is enforced afterwards by validateProvidersBlock)
+//   - cloudquery.connection may only appear in the root file
+func mergeConfigDoc(root, part *yaml.Node, source string) diag.Diagnostics {
+	if partCloudQuery := findMappingValueNode(part, "cloudquery"); partCloudQuery != nil {
+		if findMappingValueNode(partCloudQuery, "connection") != nil {
+			return diag.FromError(fmt.Errorf("%s: cloudquery.connection may only be set in the root config file", source), diag.USER)
+		}
+
+		if partProviders := findMappingValueNode(partCloudQuery, "providers"); partProviders != nil {
+			rootCloudQuery := findOrCreateMappingValueNode(root, "cloudquery")
+			rootProviders := findOrCreateSequenceValueNode(rootCloudQuery, "providers")
+			mergeRequiredProviders(rootProviders, partProviders)
+		}
+	}
+
+	if partProviders := findMappingValueNode(part, "providers"); partProviders != nil {
+		rootProviders := findOrCreateSequenceValueNode(root, "providers")
+		rootProviders.Content = append(rootProviders.Content, partProviders.Content...)
+	}
+
+	return nil
+}
+
+// mergeRequiredProviders appends entries from part into root, skipping any whose name+version
+// already exists in root.
+func mergeRequiredProviders(root, part *yaml.Node) {
+	seen := make(map[string]bool, len(root.Content))
+	for _, n := range root.Content {
+		seen[requiredProviderKey(n)] = true
+	}
+	for _, n := range part.Content {
+		key := requiredProviderKey(n)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		root.Content = append(root.Content, n)
+	}
+}
+
+func requiredProviderKey(n *yaml.Node) string {
+	return scalarMappingValue(n, "name") + "@" + scalarMappingValue(n, "version")
+}
+
+func scalarMappingValue(n *yaml.Node, key string) string {
+	if v := findMappingValueNode(n, key); v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+func findMappingValueNode(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func findOrCreateMappingValueNode(mapping *yaml.Node, key string) *yaml.Node {
+	if v := findMappingValueNode(mapping, key); v != nil {
+		return v
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+func findOrCreateSequenceValueNode(mapping *yaml.Node, key string) *yaml.Node {
+	if v := findMappingValueNode(mapping, key); v != nil {
+		return v
+	}
+	v := &yaml.Node{Kind: yaml.SequenceNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -22,10 +23,42 @@ import (
 //go:embed schema.json
 var configSchemaYAML []byte
 
+// LoadConfigFromSource loads a configuration from raw bytes, assumed to be YAML. Callers that
+// know the source format (e.g. LoadConfigFile, which infers it from the file extension) should
+// use LoadConfigFromSourceFormat instead so JSON and HCL configs are decoded correctly.
 func (p *Parser) LoadConfigFromSource(data []byte) (*Config, diag.Diagnostics) {
-	newData := os.Expand(string(data), p.getVariableValue)
-	config, diags := decodeConfig(strings.NewReader(newData))
+	return p.LoadConfigFromSourceFormat(data, FormatYAML)
+}
+
+// LoadConfigFromSourceFormat loads a configuration from raw bytes encoded in the given Format.
+func (p *Parser) LoadConfigFromSourceFormat(data []byte, format Format) (*Config, diag.Diagnostics) {
+	vars, diags := resolveVariables(data, format)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Plain $VAR/${VAR} substitution from the environment still works for backwards
+	// compatibility. It runs before ${var.X}/${env.X} interpolation and leaves those references
+	// and $$ escapes in place, so neither an escaped `$` nor a `$` inside a resolved value is
+	// expanded a second time.
+	expanded := os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$$"
+		}
+		if strings.HasPrefix(name, "var.") || strings.HasPrefix(name, "env.") {
+			return "${" + name + "}"
+		}
+		return p.getVariableValue(name)
+	})
 
+	newData, idiags := interpolate(expanded, vars)
+	diags = diags.Add(idiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	config, cdiags := decodeConfig(strings.NewReader(newData), format)
+	diags = diags.Add(cdiags)
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -39,7 +72,77 @@ func (p *Parser) LoadConfigFile(path string) (*Config, diag.Diagnostics) {
 	if diags.HasErrors() {
 		return nil, diags
 	}
-	return p.LoadConfigFromSource(contents)
+
+	format := FormatFromExtension(path)
+	if format == FormatYAML {
+		merged, mdiags := mergeConfD(path, contents)
+		diags = diags.Add(mdiags)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		contents = merged
+	}
+
+	config, cdiags := p.LoadConfigFromSourceFormat(contents, format)
+	diags = diags.Add(cdiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Applied to every config load, not just the one `init`/`add` does when a provider is first
+	// added, so a provider_source_overrides entry set (or changed) after the fact is honored by
+	// ordinary `fetch` runs against the existing config too.
+	diags = diags.Add(applyProviderSourceOverrides(config, contents, format))
+
+	// Unless the user passed --upgrade, a required provider whose version is a constraint
+	// (e.g. ">= 0.12, < 0.14") is pinned to whatever concrete version DownloadProviders last
+	// resolved it to, so repeated fetches on this machine (and in CI, once the lockfile is
+	// checked in) stay reproducible instead of silently picking up new releases.
+	if !viper.GetBool("upgrade") {
+		diags = diags.Add(pinProvidersToLockfile(config, LockfilePath(path)))
+	}
+
+	return config, diags
+}
+
+// applyProviderSourceOverrides sets each required provider's Source from the config's own
+// cloudquery.provider_source_overrides, the same map init/add already consult via
+// ResolveProviderSource when a provider is first added. A provider with no matching override is
+// left as-is, so a Source set directly on the provider block still wins.
+func applyProviderSourceOverrides(config *Config, rawData []byte, format Format) diag.Diagnostics {
+	overrides, diags := ExtractProviderSourceOverrides(rawData, format)
+	if diags.HasErrors() || len(overrides) == 0 {
+		return diags
+	}
+	for _, rp := range config.CloudQuery.Providers {
+		if src, ok := overrides[rp.Name]; ok && src != "" {
+			rp.Source = &src
+		}
+	}
+	return diags
+}
+
+// pinProvidersToLockfile overrides the version of each required provider whose version is a
+// constraint expression with the one recorded in the lockfile, if any. Providers set to "latest"
+// or an exact version, and providers not yet present in the lockfile (e.g. the first run, or a
+// provider just added to the config), are left as-is.
+func pinProvidersToLockfile(config *Config, lockPath string) diag.Diagnostics {
+	lf, diags := ReadLockfile(lockPath)
+	if diags.HasErrors() || lf == nil {
+		return diags
+	}
+	for _, rp := range config.CloudQuery.Providers {
+		if isVersionLatest(rp.Version) {
+			continue
+		}
+		if _, err := ParseVersion(rp.Version); err == nil {
+			continue
+		}
+		if locked := lf.Lookup(rp.Name); locked != nil {
+			rp.Version = locked.Version
+		}
+	}
+	return diags
 }
 
 // ProcessConfig handles the configuration after it was loaded and parsed
@@ -110,27 +213,58 @@ func validateCloudQueryProviders(providers RequiredProviders) diag.Diagnostics {
 			continue
 		}
 
-		_, err := ParseVersion(cp.Version)
-		if err != nil {
-			diags = diags.Add(diag.FromError(fmt.Errorf("Provider %q version %q is invalid. Please set to 'latest' a or valid semantic version", cp.Name, cp.Version), diag.USER))
+		if _, err := ParseVersion(cp.Version); err == nil {
+			continue
+		}
+
+		// Not an exact version; accept a Terraform-style constraint expression instead, e.g.
+		// ">= 0.12, < 0.14" or "~> 0.12.0". DownloadProviders resolves it against the registry's
+		// available versions and records the concrete version it picked in cloudquery.lock.yaml.
+		if _, err := ParseConstraint(cp.Version); err != nil {
+			diags = diags.Add(diag.FromError(fmt.Errorf("Provider %q version %q is invalid. Please set to 'latest', an exact version, or a version constraint (e.g. \">= 0.12, < 0.14\")", cp.Name, cp.Version), diag.USER))
 		}
 	}
 
 	return diags
 }
 
-func decodeConfig(r io.Reader) (*Config, diag.Diagnostics) {
+func decodeConfig(r io.Reader, format Format) (*Config, diag.Diagnostics) {
 	var yc struct {
 		CloudQuery CloudQuery  `yaml:"cloudquery" json:"cloudquery"`
 		Providers  []*Provider `yaml:"providers" json:"providers"`
+		// Variables is decoded here only so KnownFields doesn't reject it; the block is
+		// already extracted and resolved by resolveVariables before we get this far.
+		Variables []Variable `yaml:"variables" json:"variables"`
 	}
 
 	lgc := logging.GlobalConfig
 	yc.CloudQuery.Logger = &lgc
-	d := yaml.NewDecoder(r)
-	d.KnownFields(true)
-	if err := d.Decode(&yc); err != nil {
-		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse yaml"))
+
+	switch format {
+	case FormatJSON:
+		d := json.NewDecoder(r)
+		d.DisallowUnknownFields()
+		if err := d.Decode(&yc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse json"))
+		}
+	case FormatHCL:
+		hclBytes, err := io.ReadAll(r)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to read hcl"))
+		}
+		jsonBytes, err := hclToJSON(hclBytes)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse hcl"))
+		}
+		if err := json.Unmarshal(jsonBytes, &yc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse hcl"))
+		}
+	default:
+		d := yaml.NewDecoder(r)
+		d.KnownFields(true)
+		if err := d.Decode(&yc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse yaml"))
+		}
 	}
 
 	schemaLoader := gojsonschema.NewBytesLoader(configSchemaYAML)
@@ -253,7 +387,12 @@ func normalize(config *Config) {
 			continue
 		}
 
-		ver, _ := ParseVersion(cloudqueryProvider.Version)
+		// Constraint expressions (e.g. ">= 0.12, < 0.14") aren't a single version to format;
+		// they're resolved against the registry (and pinned via the lockfile) at download time.
+		ver, err := ParseVersion(cloudqueryProvider.Version)
+		if err != nil {
+			continue
+		}
 		// convert partial versions such as "0.10" to "v0.10.0"
 		cloudqueryProvider.Version = FormatVersion(ver)
 	}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the on-disk encoding of a cloudquery configuration file.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatHCL  Format = "hcl"
+)
+
+// FormatFromExtension returns the Format implied by a config file's extension, defaulting to
+// FormatYAML for unknown or missing extensions so existing `.yml`/`.yaml` configs keep working.
+func FormatFromExtension(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".hcl":
+		return FormatHCL
+	default:
+		return FormatYAML
+	}
+}
+
+// EncodeJSON transcodes a parsed yaml.Node document into equivalent JSON bytes. This works
+// trivially because YAML is a superset of JSON's data model.
+func EncodeJSON(doc *yaml.Node) ([]byte, error) {
+	var v interface{}
+	if err := doc.Decode(&v); err != nil {
+		return nil, diag.WrapError(err)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, diag.WrapError(err)
+	}
+	return b, nil
+}
+
+// EncodeHCL transcodes a parsed yaml.Node document into an equivalent HCL file by walking
+// the node tree and emitting a block for each mapping value (e.g. `cloudquery { ... }`), one
+// repeated block per item for a sequence of mappings (e.g. `providers { ... }`), and attributes
+// for scalars and other sequences.
+func EncodeHCL(doc *yaml.Node) ([]byte, error) {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	if doc.Kind != yaml.MappingNode {
+		return nil, diag.FromError(errNotAMapping, diag.INTERNAL)
+	}
+	if err := writeHCLMapping(body, doc); err != nil {
+		return nil, err
+	}
+	return f.Bytes(), nil
+}
+
+func writeHCLMapping(body *hclwrite.Body, node *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		val := node.Content[i+1]
+		switch val.Kind {
+		case yaml.MappingNode:
+			block := body.AppendNewBlock(key, nil)
+			if err := writeHCLMapping(block.Body(), val); err != nil {
+				return err
+			}
+		case yaml.SequenceNode:
+			if allMappings(val) {
+				for _, item := range val.Content {
+					block := body.AppendNewBlock(key, nil)
+					if err := writeHCLMapping(block.Body(), item); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			ctyVal, err := yamlNodeToCty(val)
+			if err != nil {
+				return err
+			}
+			body.SetAttributeValue(key, ctyVal)
+		default:
+			ctyVal, err := yamlNodeToCty(val)
+			if err != nil {
+				return err
+			}
+			body.SetAttributeValue(key, ctyVal)
+		}
+	}
+	return nil
+}
+
+func allMappings(seq *yaml.Node) bool {
+	for _, item := range seq.Content {
+		if item.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+func yamlNodeToCty(node *yaml.Node) (cty.Value, error) {
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return cty.NilVal, diag.WrapError(err)
+	}
+	return goValueToCty(v), nil
+}
+
+func goValueToCty(v interface{}) cty.Value {
+	switch t := v.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType)
+	case bool:
+		return cty.BoolVal(t)
+	case int:
+		return cty.NumberIntVal(int64(t))
+	case float64:
+		return cty.NumberFloatVal(t)
+	case string:
+		return cty.StringVal(t)
+	case []interface{}:
+		if len(t) == 0 {
+			return cty.ListValEmpty(cty.DynamicPseudoType)
+		}
+		vals := make([]cty.Value, len(t))
+		for i, item := range t {
+			vals[i] = goValueToCty(item)
+		}
+		return cty.TupleVal(vals)
+	case map[string]interface{}:
+		vals := make(map[string]cty.Value, len(t))
+		for k, item := range t {
+			vals[k] = goValueToCty(item)
+		}
+		return cty.ObjectVal(vals)
+	default:
+		return cty.StringVal("")
+	}
+}
+
+var errNotAMapping = errors.New("cannot encode a non-mapping document as HCL")
+
+// hclToJSON parses an HCL document and re-emits it as JSON with the same shape decodeConfig
+// expects from YAML/JSON sources: blocks become nested objects, and a block type repeated more
+// than once becomes an array of objects (the inverse of writeHCLMapping). We parse with
+// hclsyntax directly (rather than going through a hcl.BodySchema) since the shape of the config
+// isn't known ahead of time; it's validated against the JSON schema right after decoding.
+func hclToJSON(src []byte) ([]byte, error) {
+	hclFile, diags := hclsyntax.ParseConfig(src, "cloudquery.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := hclFile.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errNotAMapping
+	}
+	m, err := hclBodyToMap(body)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func hclBodyToMap(body *hclsyntax.Body) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(body.Attributes)+len(body.Blocks))
+	for name, attr := range body.Attributes {
+		v, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		jv, err := ctyjson.Marshal(v, v.Type())
+		if err != nil {
+			return nil, err
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(jv, &decoded); err != nil {
+			return nil, err
+		}
+		result[name] = decoded
+	}
+
+	blocksByType := make(map[string][]map[string]interface{})
+	for _, block := range body.Blocks {
+		blockMap, err := hclBodyToMap(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		blocksByType[block.Type] = append(blocksByType[block.Type], blockMap)
+	}
+	for t, blocks := range blocksByType {
+		// repeatableBlockTypes decode into a Go slice (e.g. `Providers []*Provider`) no matter
+		// how many times they appear, so a single `providers { ... }` block must still become a
+		// one-element JSON array, not a bare object, or it fails schema validation/decoding the
+		// same way a single-element YAML list wouldn't.
+		if len(blocks) == 1 && !repeatableBlockTypes[t] {
+			result[t] = blocks[0]
+			continue
+		}
+		result[t] = blocks
+	}
+
+	return result, nil
+}
+
+// repeatableBlockTypes are the HCL block types that always decode into a Go slice, regardless of
+// how many times they're repeated in the source file.
+var repeatableBlockTypes = map[string]bool{
+	"providers": true,
+	"variables": true,
+}
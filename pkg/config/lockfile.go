@@ -0,0 +1,84 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// LockedProvider records the concrete version a required provider was resolved to the last time
+// DownloadProviders ran, mirroring Terraform's .terraform.lock.hcl. It doesn't pin an artifact
+// hash: DownloadProviders (pkg/ui/console) doesn't currently surface one to resolve against, so a
+// Sha256 field here would sit unset and unchecked, recording a guarantee this package can't keep.
+type LockedProvider struct {
+	Name    string `yaml:"name"`
+	Source  string `yaml:"source,omitempty"`
+	Version string `yaml:"version"`
+}
+
+// Lockfile is the schema of cloudquery.lock.yaml: one resolved version per required provider,
+// keyed by name. No artifact hash is recorded (see LockedProvider).
+type Lockfile struct {
+	Providers []LockedProvider `yaml:"providers"`
+}
+
+// LockfilePath returns the cloudquery.lock.yaml path that sits next to a given config file.
+func LockfilePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "cloudquery.lock.yaml")
+}
+
+// ReadLockfile loads a lockfile, returning a nil Lockfile (and no error) if it doesn't exist
+// yet, e.g. before the first `cloudquery init`/DownloadProviders run has populated it.
+func ReadLockfile(path string) (*Lockfile, diag.Diagnostics) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to read lockfile %s", path))
+	}
+	var lf Lockfile
+	if err := yaml.Unmarshal(b, &lf); err != nil {
+		return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse lockfile %s", path))
+	}
+	return &lf, nil
+}
+
+// Write persists the lockfile to path.
+func (l *Lockfile) Write(path string) diag.Diagnostics {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return diag.FromError(err, diag.INTERNAL, diag.WithSummary("Failed to marshal lockfile"))
+	}
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return diag.FromError(err, diag.USER, diag.WithSummary("Failed to write lockfile %s", path))
+	}
+	return nil
+}
+
+// Lookup returns the locked entry for a provider name, or nil if it isn't locked yet.
+func (l *Lockfile) Lookup(name string) *LockedProvider {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Providers {
+		if l.Providers[i].Name == name {
+			return &l.Providers[i]
+		}
+	}
+	return nil
+}
+
+// Put inserts or updates the locked entry for a provider, keyed by name.
+func (l *Lockfile) Put(entry LockedProvider) {
+	for i := range l.Providers {
+		if l.Providers[i].Name == entry.Name {
+			l.Providers[i] = entry
+			return
+		}
+	}
+	l.Providers = append(l.Providers, entry)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudquery/cloudquery/pkg/plugin/registry"
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+)
+
+// LockResolvedProviders resolves every required provider whose version is a constraint
+// expression (e.g. ">= 0.12, < 0.14") against the registry's available releases, picks the
+// newest match, and persists the result to configPath's cloudquery.lock.yaml. init/add call this
+// once DownloadProviders has finished, so the concrete version it actually downloaded is the one
+// recorded; a later LoadConfigFile pins back to that same version via pinProvidersToLockfile,
+// keeping repeated fetches (and CI, once the lockfile is checked in) reproducible.
+func LockResolvedProviders(ctx context.Context, cfg *Config, configPath string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	lf, rdiags := ReadLockfile(LockfilePath(configPath))
+	diags = diags.Add(rdiags)
+	if diags.HasErrors() {
+		return diags
+	}
+	if lf == nil {
+		lf = &Lockfile{}
+	}
+
+	for _, rp := range cfg.CloudQuery.Providers {
+		if isVersionLatest(rp.Version) {
+			continue
+		}
+		if _, err := ParseVersion(rp.Version); err == nil {
+			// Already an exact version; nothing to resolve.
+			continue
+		}
+		constraint, err := ParseConstraint(rp.Version)
+		if err != nil {
+			// Already reported by validateCloudQueryProviders.
+			continue
+		}
+
+		source := rp.Name
+		if rp.Source != nil && *rp.Source != "" {
+			source = *rp.Source
+		}
+		available, err := registry.ListVersions(ctx, source)
+		if err != nil {
+			diags = diags.Add(diag.FromError(err, diag.INTERNAL, diag.WithSummary("Failed to list available versions for provider %s", rp.Name)))
+			continue
+		}
+		SortVersionsDescending(available)
+		resolved := ResolveConstraint(constraint, available)
+		if resolved == nil {
+			diags = diags.Add(diag.FromError(fmt.Errorf("no version of provider %s satisfies constraint %q", rp.Name, rp.Version), diag.USER))
+			continue
+		}
+
+		lf.Put(LockedProvider{Name: rp.Name, Source: source, Version: FormatVersion(resolved)})
+	}
+
+	return diags.Add(lf.Write(LockfilePath(configPath)))
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderMirrorEnvVar points at a filesystem or HTTPS mirror to resolve provider sources
+// against instead of the GitHub-hosted registry, unblocking air-gapped installs.
+const ProviderMirrorEnvVar = "CLOUDQUERY_PROVIDER_MIRROR"
+
+// ExtractProviderSourceOverrides parses the top-level `cloudquery.provider_source_overrides` map
+// straight out of raw config bytes, without going through the strict Config/CloudQuery decode.
+// This lets `init`/`add` honor overrides already present in a config file they're about to
+// append to, e.g.:
+//
+//	cloudquery:
+//	  provider_source_overrides:
+//	    aws: mycorp/aws-internal
+//	    gcp: file:///opt/cq-plugins/gcp
+func ExtractProviderSourceOverrides(data []byte, format Format) (map[string]string, diag.Diagnostics) {
+	var doc struct {
+		CloudQuery struct {
+			ProviderSourceOverrides map[string]string `yaml:"provider_source_overrides" json:"provider_source_overrides"`
+		} `yaml:"cloudquery" json:"cloudquery"`
+	}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse provider source overrides"))
+		}
+	case FormatHCL:
+		jsonBytes, err := hclToJSON(data)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse provider source overrides"))
+		}
+		if err := json.Unmarshal(jsonBytes, &doc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse provider source overrides"))
+		}
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse provider source overrides"))
+		}
+	}
+
+	return doc.CloudQuery.ProviderSourceOverrides, nil
+}
+
+// ProviderMirror returns the configured plugin mirror, honoring --plugin-mirror over the
+// CLOUDQUERY_PROVIDER_MIRROR environment variable. An empty string means no mirror is configured
+// and the default registry should be used.
+func ProviderMirror() string {
+	if m := viper.GetString("plugin-mirror"); m != "" {
+		return m
+	}
+	return os.Getenv(ProviderMirrorEnvVar)
+}
+
+// ResolveProviderSource returns the source cloudquery should record for a provider, applying (in
+// priority order) an explicit per-provider override, then a configured mirror, then falling back
+// to defaultSource (typically empty, meaning "use the default registry").
+func ResolveProviderSource(overrides map[string]string, providerName, defaultSource string) string {
+	if src, ok := overrides[providerName]; ok && src != "" {
+		return src
+	}
+	if mirror := ProviderMirror(); mirror != "" {
+		return strings.TrimSuffix(mirror, "/") + "/" + providerName
+	}
+	return defaultSource
+}
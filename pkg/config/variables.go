@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cloudquery/cq-provider-sdk/provider/diag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// variableEnvPrefix mirrors Terraform's TF_VAR_ convention: an undeclared-on-the-CLI variable
+// named `account_id` can be supplied via CLOUDQUERY_VAR_ACCOUNT_ID.
+const variableEnvPrefix = "CLOUDQUERY_VAR_"
+
+// Variable describes a single entry in a configuration file's top-level `variables:` block.
+type Variable struct {
+	Name        string `yaml:"name" json:"name"`
+	Type        string `yaml:"type" json:"type"`
+	Default     string `yaml:"default" json:"default"`
+	Description string `yaml:"description" json:"description"`
+}
+
+var interpRegexp = regexp.MustCompile(`\$\$|\$\{(var|env)\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolate resolves `${var.X}` and `${env.X}` references in data against the given resolved
+// variable values, and unescapes `$$` to a literal `$`. Any other `$`-syntax (bare `$VAR`,
+// `${VAR}`) is left untouched; LoadConfigFromSourceFormat applies its os.Expand pass before this.
+func interpolate(data string, vars map[string]string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	result := interpRegexp.ReplaceAllStringFunc(data, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		groups := interpRegexp.FindStringSubmatch(match)
+		kind, name := groups[1], groups[2]
+		if kind == "env" {
+			return os.Getenv(name)
+		}
+		val, ok := vars[name]
+		if !ok {
+			diags = diags.Add(diag.FromError(fmt.Errorf("variable %q is used in config but not declared in the variables block nor provided via --var/--var-file", name), diag.USER))
+			return match
+		}
+		return val
+	})
+	return result, diags
+}
+
+// resolveVariables extracts the `variables:` block from the raw config bytes (without
+// KnownFields, since the rest of the document won't match the Variable schema) and resolves a
+// value for each declared variable using Terraform-style precedence:
+// --var flag > --var-file > CLOUDQUERY_VAR_* env > declared default.
+func resolveVariables(data []byte, format Format) (map[string]string, diag.Diagnostics) {
+	variables, diags := decodeVariablesBlock(data, format)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	fileVars, diags := varFileValues()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	cliVars := cliVarValues()
+
+	resolved := make(map[string]string, len(variables))
+	for _, v := range variables {
+		switch {
+		case cliVars[v.Name] != "":
+			resolved[v.Name] = cliVars[v.Name]
+		case fileVars[v.Name] != "":
+			resolved[v.Name] = fileVars[v.Name]
+		default:
+			if envVal, ok := os.LookupEnv(variableEnvPrefix + strings.ToUpper(v.Name)); ok {
+				resolved[v.Name] = envVal
+				continue
+			}
+			resolved[v.Name] = v.Default
+		}
+	}
+	// --var/--var-file can also set values for variables not declared in the block.
+	for name, val := range fileVars {
+		if _, ok := resolved[name]; !ok {
+			resolved[name] = val
+		}
+	}
+	for name, val := range cliVars {
+		resolved[name] = val
+	}
+	return resolved, nil
+}
+
+// decodeVariablesBlock extracts just the `variables:` block from raw config bytes in the given
+// format. It's decoded separately from (and before) the rest of the document via decodeConfig, so
+// a document that doesn't parse as YAML - an ordinary .hcl config, say - doesn't fail here just
+// because resolveVariables always runs ahead of format-aware decoding.
+func decodeVariablesBlock(data []byte, format Format) ([]Variable, diag.Diagnostics) {
+	var vb struct {
+		Variables []Variable `yaml:"variables" json:"variables"`
+	}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &vb); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse variables block"))
+		}
+	case FormatHCL:
+		jsonBytes, err := hclToJSON(data)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse variables block"))
+		}
+		if err := json.Unmarshal(jsonBytes, &vb); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse variables block"))
+		}
+	default:
+		if err := yaml.Unmarshal(data, &vb); err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse variables block"))
+		}
+	}
+
+	return vb.Variables, nil
+}
+
+// cliVarValues parses repeated `--var name=value` flags into a map.
+func cliVarValues() map[string]string {
+	result := make(map[string]string)
+	for _, kv := range viper.GetStringSlice("var") {
+		name, val, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		result[name] = val
+	}
+	return result
+}
+
+// varFileValues reads and merges every `--var-file` into a single name->value map. Files ending
+// in `.yml`/`.yaml` are parsed as a flat YAML mapping; anything else (e.g. `.tfvars`) is parsed
+// as simple `name = value` lines, matching Terraform's tfvars syntax for the common case of
+// unquoted/quoted string and number values.
+func varFileValues() (map[string]string, diag.Diagnostics) {
+	result := make(map[string]string)
+	for _, path := range viper.GetStringSlice("var-file") {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to read var file %q", path))
+		}
+
+		if strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml") {
+			var m map[string]string
+			if err := yaml.Unmarshal(contents, &m); err != nil {
+				return nil, diag.FromError(err, diag.USER, diag.WithSummary("Failed to parse var file %q", path))
+			}
+			for k, v := range m {
+				result[k] = v
+			}
+			continue
+		}
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, val, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			result[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(val), `"`)
+		}
+	}
+	return result, nil
+}
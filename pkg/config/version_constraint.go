@@ -0,0 +1,35 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ParseConstraint parses a Terraform-style version constraint expression, e.g.
+// ">= 0.12, < 0.14" or "~> 0.12.0", as opposed to ParseVersion which expects a single exact
+// version.
+func ParseConstraint(expr string) (*semver.Constraints, error) {
+	return semver.NewConstraint(expr)
+}
+
+// ResolveConstraint returns the newest version in available that satisfies constraints, or nil if
+// none match.
+func ResolveConstraint(constraints *semver.Constraints, available []*semver.Version) *semver.Version {
+	var best *semver.Version
+	for _, v := range available {
+		if !constraints.Check(v) {
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// SortVersionsDescending sorts versions newest-first, for callers that fetch a provider's
+// available versions from the registry and want to resolve a constraint against them.
+func SortVersionsDescending(versions []*semver.Version) {
+	sort.Slice(versions, func(i, j int) bool { return versions[i].GreaterThan(versions[j]) })
+}
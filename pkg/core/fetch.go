@@ -8,12 +8,14 @@ import (
 	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudquery/cloudquery/internal/analytics"
 	cqsort "github.com/cloudquery/cloudquery/internal/sort"
 	"github.com/cloudquery/cloudquery/pkg/config"
 	"github.com/cloudquery/cloudquery/pkg/core/database"
+	"github.com/cloudquery/cloudquery/pkg/core/logger"
 	"github.com/cloudquery/cloudquery/pkg/core/state"
 	cqerrors "github.com/cloudquery/cloudquery/pkg/errors"
 	"github.com/cloudquery/cloudquery/pkg/plugin"
@@ -23,8 +25,6 @@ import (
 	"github.com/cloudquery/cq-provider-sdk/provider/diag"
 	"github.com/cloudquery/cq-provider-sdk/provider/schema"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"github.com/thoas/go-funk"
 )
 
@@ -39,6 +39,9 @@ type ProviderFetchSummary struct {
 	FetchedResources      map[string]ResourceFetchSummary `json:"fetch_resources,omitempty"`
 	Status                FetchStatus                     `json:"status,omitempty"`
 	Duration              time.Duration                   `json:"duration,omitempty"`
+	// Mode records whether this provider actually ran a delta fetch, as opposed to falling back
+	// to a full sync (e.g. no prior subscription, or a provider version bump).
+	Mode FetchMode `json:"mode,omitempty"`
 }
 
 type ResourceFetchSummary struct {
@@ -53,14 +56,54 @@ type ResourceFetchSummary struct {
 	TelemetryEvents []analytics.TelemetryEvent `json:"-"`
 	// Duration in seconds
 	Duration time.Duration `json:"duration,omitempty"`
+	// Version is the provider-reported version of this resource's data as of this fetch. It
+	// feeds the KnownVersions sent on the next delta fetch; empty when the provider doesn't
+	// report versions.
+	Version string `json:"version,omitempty"`
+}
+
+// FetchMode controls whether Fetch asks providers for a full resource sync or an incremental
+// delta sync driven by per-resource subscription state persisted in state.Client.
+type FetchMode int
+
+const (
+	// FetchFull requests every matching resource in full, ignoring any prior subscription.
+	FetchFull FetchMode = iota
+	// FetchDelta asks the provider to only return resources that changed since the last
+	// successful fetch, using the per-resource versions recorded in the resource subscription.
+	// A resource with no prior recorded version still gets a full sync on its first delta fetch.
+	FetchDelta
+)
+
+func (m FetchMode) String() string {
+	if m == FetchDelta {
+		return "delta"
+	}
+	return "full"
 }
 
 type FetchUpdateCallback func(update FetchUpdate)
 
+// FetchUpdateKind distinguishes a normal progress update (the default, zero value) from a
+// Draining update, emitted once when a canceled fetch starts waiting out its DrainTimeout for
+// already-started resources to finish.
+type FetchUpdateKind int
+
+const (
+	FetchUpdateProgress FetchUpdateKind = iota
+	FetchUpdateDraining
+)
+
 type FetchUpdate struct {
 	Name    string
 	Alias   string
 	Version string
+	// Kind is FetchUpdateProgress for a normal update, or FetchUpdateDraining for the one-time
+	// notice that the fetch was canceled and is now draining. The CLI can use this to render
+	// "waiting for N resources to finish...".
+	Kind FetchUpdateKind
+	// DrainingCount is the number of resources still in flight when Kind is FetchUpdateDraining.
+	DrainingCount int
 	// Map of resources that have finished fetching
 	FinishedResources map[string]bool
 	// Amount of resources collected so far
@@ -95,6 +138,53 @@ type FetchOptions struct {
 	ExtraFields map[string]interface{}
 	// Optional: unique identifier for the fetch, if this isn't given, a random one is generated.
 	FetchId uuid.UUID
+	// Optional: Mode selects between a full resource sync (the default, FetchFull) and an
+	// incremental FetchDelta sync driven by the per-resource subscription state recorded in
+	// state.Client. Providers without a usable prior subscription still run a full sync.
+	Mode FetchMode
+	// Optional: ResumePolicy controls whether a fetch sharing FetchId with a prior incomplete
+	// run picks up where that run left off instead of refetching everything. Defaults to
+	// ResumeNever, so FetchId alone doesn't implicitly enable resuming.
+	ResumePolicy ResumePolicy
+	// Optional: DrainTimeout bounds how long a canceled fetch keeps the provider call alive to
+	// let resources that had already started flush to storage. It only delays the provider call
+	// itself; ctx cancelation still stops Fetch from starting any new providers. Defaults to 30s.
+	DrainTimeout time.Duration
+	// Optional: Logger receives every log line Fetch and everything it calls produce. Defaults
+	// to a zerolog-backed adapter (see pkg/core/logger) wrapping the package-global logger, so
+	// callers that don't set it see the same output as before Logger existed. Embedders can
+	// supply their own to route CloudQuery's logs into their own structured logging stack.
+	Logger logger.Logger
+}
+
+type resumeMode int
+
+const (
+	resumeNever resumeMode = iota
+	resumeIfRecent
+	resumeAlways
+)
+
+// ResumePolicy controls whether Fetch resumes a prior incomplete run that shares the same
+// FetchId, picking up from the resources already checkpointed as complete.
+type ResumePolicy struct {
+	mode   resumeMode
+	within time.Duration
+}
+
+// ResumeNever is the default: every fetch starts from scratch, ignoring any checkpointed
+// progress left behind by a prior run that shared the same FetchId.
+var ResumeNever = ResumePolicy{mode: resumeNever}
+
+// ResumeAlways resumes a prior incomplete run for FetchId regardless of how long ago it
+// checkpointed progress.
+var ResumeAlways = ResumePolicy{mode: resumeAlways}
+
+// ResumeIfRecent resumes a prior incomplete run for FetchId only if its last checkpoint is
+// within the given duration; an older run is abandoned and the fetch starts from scratch, e.g.
+// to avoid resuming a fetch whose underlying config may have drifted since a days-old crash.
+func ResumeIfRecent(within time.Duration) ResumePolicy {
+	return ResumePolicy{mode: resumeIfRecent, within: within}
 }
 
 type fetchResult struct {
@@ -108,6 +198,9 @@ const (
 	FetchCanceled
 	FetchFinished
 	FetchPartial
+	// FetchResumed means the fetch completed successfully by picking up from a checkpointed
+	// prior run, rather than fetching every resource from scratch.
+	FetchResumed
 )
 
 func (fs FetchStatus) String() string {
@@ -122,6 +215,8 @@ func (fs FetchStatus) String() string {
 		return "partial"
 	case FetchConfigureFailed:
 		return "configure_failed"
+	case FetchResumed:
+		return "resumed"
 	default:
 		return "unknown"
 	}
@@ -164,6 +259,7 @@ func (p ProviderFetchSummary) Properties() map[string]interface{} {
 		"fetch_duration":              math.Round(p.Duration.Seconds()*100) / 100,
 		"fetch_diags":                 analytics.SummarizeDiagnostics(p.Diagnostics()),
 		"fetch_status":                p.Status.String(),
+		"fetch_mode":                  p.Mode.String(),
 	}
 }
 
@@ -206,7 +302,11 @@ func Fetch(ctx context.Context, sta *state.Client, storage database.Storage, pm
 	}
 	// set metadata we want to pass to
 	metadata := map[string]interface{}{schema.FetchIdMetaKey: fetchId}
-	log.Info().Interface("extra_fields", opts.ExtraFields).Msg("Received fetch request")
+	lg := opts.Logger
+	if lg == nil {
+		lg = logger.Default()
+	}
+	lg.Info("Received fetch request", "extra_fields", opts.ExtraFields)
 
 	var (
 		diags          diag.Diagnostics
@@ -219,9 +319,13 @@ func Fetch(ctx context.Context, sta *state.Client, storage database.Storage, pm
 	if err != nil {
 		return nil, diag.FromError(err, diag.INTERNAL)
 	}
+	drainTimeout := opts.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = 30 * time.Second
+	}
 	for _, providerInfo := range opts.ProvidersInfo {
 		if len(providerInfo.Config.Resources) == 0 {
-			log.Warn().Str("provider", providerInfo.Config.Name).Str("alias", providerInfo.Config.Alias).Msg("Skipping provider which configured with 0 resources to fetch")
+			lg.Warn("Skipping provider which configured with 0 resources to fetch", "provider", providerInfo.Config.Name, "alias", providerInfo.Config.Alias)
 			diags = diags.Add(diag.FromError(nil, diag.INTERNAL, diag.WithSeverity(diag.WARNING), diag.WithSummary("skipping provider %s which configured with 0 resources to fetch", providerInfo.Config.Name)))
 			continue
 		}
@@ -229,15 +333,25 @@ func Fetch(ctx context.Context, sta *state.Client, storage database.Storage, pm
 		go func(info ProviderInfo) {
 			defer wg.Done()
 			start := time.Now()
-			s, d := runProviderFetch(ctx, pm, info, dsnURI, metadata, opts)
-			if _, ok := ctx.Deadline(); ok {
-				fetchSummaries <- fetchResult{s, d}
-				return
+			s, d := runProviderFetch(ctx, sta, storage, pm, info, dsnURI, fetchId, metadata, opts, drainTimeout, lg)
+			// The subscription is only advanced once the whole provider fetch completes
+			// successfully, whether that's a fresh FetchFinished or a FetchResumed run that
+			// picked up a checkpointed fetch and finished it: a partial or failed fetch must not
+			// move resource versions forward, or the next delta fetch would silently miss rows.
+			if opts.Mode == FetchDelta && (s.Status == FetchFinished || s.Status == FetchResumed) {
+				if err := sta.SaveResourceSubscription(ctx, buildResourceSubscription(info, s)); err != nil {
+					d = d.Add(diag.FromError(err, diag.INTERNAL))
+				}
 			}
-			// TODO: if context deadline exceeds in fetch, do we still want to run the save?
-			if err := sta.SaveFetchSummary(ctx, createFetchSummary(fetchId, start, s)); err != nil {
+			// The summary is always saved, even for a canceled fetch: a drained fetch still has
+			// real resource summaries worth keeping, and a caller polling fetch history
+			// shouldn't see a cancelation as if nothing had happened. Saved against a detached
+			// context since ctx may already be canceled by the time a drain finishes.
+			saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := sta.SaveFetchSummary(saveCtx, createFetchSummary(fetchId, start, s)); err != nil {
 				d = d.Add(diag.FromError(err, diag.INTERNAL))
 			}
+			cancel()
 			fetchSummaries <- fetchResult{s, d}
 		}(providerInfo)
 	}
@@ -260,24 +374,27 @@ func Fetch(ctx context.Context, sta *state.Client, storage database.Storage, pm
 	return response, filtered
 }
 
-func runProviderFetch(ctx context.Context, pm *plugin.Manager, info ProviderInfo, dsnURI string, metadata map[string]interface{}, opts *FetchOptions) (*ProviderFetchSummary, diag.Diagnostics) {
+func runProviderFetch(ctx context.Context, sta *state.Client, storage database.Storage, pm *plugin.Manager, info ProviderInfo, dsnURI string, fetchId uuid.UUID, metadata map[string]interface{}, opts *FetchOptions, drainTimeout time.Duration, lg logger.Logger) (*ProviderFetchSummary, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	cfg := info.Config
-	pLog := log.With().Str("provider", cfg.Name).Str("alias", cfg.Alias).Logger()
+	pLog := lg.With("provider", cfg.Name, "alias", cfg.Alias)
 
-	pLog.Debug().Str("name", info.Provider.String()).Str("alias", cfg.Alias).Msg("Creating provider plugin")
+	pLog.Debug("Creating provider plugin", "name", info.Provider.String(), "alias", cfg.Alias)
 	providerPlugin, err := pm.CreatePlugin(&plugin.CreationOptions{
 		Provider: info.Provider,
 		Alias:    cfg.Alias,
 		Env:      cfg.Env,
+		// Logger lets the plugin manager bridge subprocess (hclog) output from this provider
+		// into the same sink the rest of this fetch is logging to.
+		Logger: pLog,
 	})
 	if err != nil {
-		pLog.Error().Err(err).Msg("Failed to create provider plugin")
+		pLog.Error("Failed to create provider plugin", "error", err)
 		return nil, diag.FromError(err, diag.INTERNAL)
 	}
 	defer pm.ClosePlugin(providerPlugin)
 
-	pLog.Info().Msg("Requesting provider to configure")
+	pLog.Info("Requesting provider to configure")
 	resp, err := providerPlugin.Provider().ConfigureProvider(ctx, &cqproto.ConfigureProviderRequest{
 		CloudQueryVersion: Version,
 		Connection: cqproto.ConnectionDetails{
@@ -286,7 +403,7 @@ func runProviderFetch(ctx context.Context, pm *plugin.Manager, info ProviderInfo
 		Config: cfg.ConfigBytes,
 	})
 	if err != nil {
-		pLog.Error().Err(err).Msg("Failed to configure provider")
+		pLog.Error("Failed to configure provider", "error", err)
 		var (
 			d   diag.Diagnostics
 			sts FetchStatus
@@ -319,14 +436,195 @@ func runProviderFetch(ctx context.Context, pm *plugin.Manager, info ProviderInfo
 		}, diags
 	}
 
-	pLog.Info().Msg("Provider configured successfully")
-	summary, fetchDiags := executeFetch(ctx, pLog, providerPlugin, info, metadata, opts.UpdateCallback)
+	pLog.Info("Provider configured successfully")
+
+	// Keyed by (provider name, alias, provider version) so upgrading the provider forces a
+	// full resync instead of trusting versions reported by a different binary.
+	mode := opts.Mode
+	var knownVersions map[string]string
+	var priorResources []string
+	if mode == FetchDelta {
+		sub, err := sta.GetResourceSubscription(ctx, info.Provider.Name, cfg.Alias, providerPlugin.Version())
+		switch {
+		case err != nil:
+			pLog.Warn("Failed to load resource subscription, falling back to a full fetch", "error", err)
+			mode = FetchFull
+		case sub == nil:
+			pLog.Info("No prior resource subscription, running a full fetch")
+			mode = FetchFull
+		default:
+			knownVersions = deltaEligibleVersions(cfg.Resources, sub)
+			// Tracked separately from knownVersions so executeFetch can tell a resource that was
+			// dropped from this run's scope (narrowed wildcard, or removed outright) apart from
+			// one that's simply new: only the former should have its data deleted.
+			priorResources = sub.Resources
+		}
+	}
+
+	// A resume only kicks in when the caller explicitly asked for it via ResumePolicy; FetchId
+	// alone doesn't implicitly resume, since most callers pass a fresh, random one every time.
+	var priorSummary *ProviderFetchSummary
+	var completed map[string]bool
+	if opts.ResumePolicy.mode != resumeNever {
+		checkpoint, err := sta.GetFetchCheckpoint(ctx, fetchId, info.Provider.Name, cfg.Alias)
+		switch {
+		case err != nil:
+			pLog.Warn("Failed to load fetch checkpoint, starting from scratch", "error", err)
+		case checkpoint == nil:
+			// No checkpointed progress for this FetchId yet; nothing to resume.
+		case opts.ResumePolicy.mode == resumeIfRecent && time.Since(checkpoint.UpdatedAt) > opts.ResumePolicy.within:
+			pLog.Info("Fetch checkpoint too old to resume, starting from scratch", "checkpoint_age", time.Since(checkpoint.UpdatedAt))
+		default:
+			priorSummary, completed = checkpointToSummary(info, checkpoint), checkpoint.CompletedResources
+			pLog.Info("Resuming fetch from checkpoint", "completed_resources", len(completed))
+		}
+	}
+
+	summary, fetchDiags := executeFetch(ctx, pLog, providerPlugin, info, metadata, opts.UpdateCallback, storage, fetchId, sta, mode, knownVersions, priorResources, completed, drainTimeout)
+	summary.Mode = mode
+	if priorSummary != nil {
+		mergeResumedSummary(summary, priorSummary)
+	}
 	diags = diags.Add(convertToFetchDiags(fetchDiags, info.Provider.Name, providerPlugin.Version()))
 
 	return summary, diags
 }
 
-func executeFetch(ctx context.Context, pLog zerolog.Logger, providerPlugin plugin.Plugin, info ProviderInfo, metadata map[string]interface{}, callback FetchUpdateCallback) (*ProviderFetchSummary, diag.Diagnostics) {
+// checkpointToSummary reconstructs a *ProviderFetchSummary from a state-local checkpoint record,
+// so mergeResumedSummary can fold it into a resumed run the same way it would a fully-typed prior
+// summary. state.FetchCheckpoint can't carry a *ProviderFetchSummary field directly (pkg/core
+// imports pkg/core/state, so the reverse dependency isn't possible), hence the conversion here.
+func checkpointToSummary(info ProviderInfo, checkpoint *state.FetchCheckpoint) *ProviderFetchSummary {
+	resources := make(map[string]ResourceFetchSummary, len(checkpoint.Resources))
+	var total uint64
+	for name, r := range checkpoint.Resources {
+		resources[name] = ResourceFetchSummary{
+			Status:        r.Status,
+			ResourceCount: r.ResourceCount,
+		}
+		total += r.ResourceCount
+	}
+	return &ProviderFetchSummary{
+		Name:                  info.Provider.Name,
+		Alias:                 info.Config.Alias,
+		FetchedResources:      resources,
+		TotalResourcesFetched: total,
+	}
+}
+
+// mergeResumedSummary folds a checkpointed prior partial summary into the summary of the
+// resumed run's own (smaller) resource set, so the final response reflects the whole logical
+// fetch rather than just the resources that still needed fetching this time.
+func mergeResumedSummary(summary, prior *ProviderFetchSummary) {
+	for name, rs := range prior.FetchedResources {
+		if _, ok := summary.FetchedResources[name]; !ok {
+			summary.FetchedResources[name] = rs
+			summary.TotalResourcesFetched += rs.ResourceCount
+		}
+	}
+	if summary.Status == FetchFinished {
+		summary.Status = FetchResumed
+	}
+}
+
+// deltaEligibleVersions returns the subset of a prior subscription's resource versions that are
+// still eligible to drive a delta fetch this run: resources already known last time that are
+// still covered by an explicit name, or by a wildcard pattern that was already present in the
+// prior subscription. A resource that's new this run (never fetched before), or only newly
+// reachable through a wildcard pattern the user just added, is left out so it gets a first-time
+// full sync, matching the invariant that new resource types never start out "delta". Whether a
+// wildcard is new is tracked per pattern, not globally, so adding one new wildcard only forces a
+// full sync for the resources it newly covers, leaving resources under other, already-known
+// wildcards on delta.
+func deltaEligibleVersions(requested []string, sub *state.ResourceSubscription) map[string]string {
+	knownResources := make(map[string]bool, len(sub.Resources))
+	for _, r := range sub.Resources {
+		knownResources[r] = true
+	}
+	knownWildcards := make(map[string]bool, len(sub.Wildcards))
+	for _, w := range sub.Wildcards {
+		knownWildcards[w] = true
+	}
+
+	explicit := make(map[string]bool)
+	priorWildcards := make([]string, 0)
+	for _, r := range requested {
+		if strings.Contains(r, "*") {
+			if knownWildcards[r] {
+				priorWildcards = append(priorWildcards, r)
+			}
+		} else {
+			explicit[r] = true
+		}
+	}
+
+	known := make(map[string]string, len(sub.ResourceVersions))
+	for name, version := range sub.ResourceVersions {
+		if !knownResources[name] {
+			continue
+		}
+		if explicit[name] || matchesAnyWildcard(name, priorWildcards) {
+			known[name] = version
+		}
+	}
+	return known
+}
+
+// matchesAnyWildcard reports whether name is covered by any of the given `resource.*` patterns.
+func matchesAnyWildcard(name string, wildcards []string) bool {
+	for _, w := range wildcards {
+		prefix := strings.TrimSuffix(w, "*")
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// droppedResources returns the entries of prior that are no longer present in current, i.e.
+// resource types the prior subscription covered that this run's normalized resource list doesn't.
+func droppedResources(prior, current []string) []string {
+	cur := make(map[string]bool, len(current))
+	for _, r := range current {
+		cur[r] = true
+	}
+	var dropped []string
+	for _, r := range prior {
+		if !cur[r] {
+			dropped = append(dropped, r)
+		}
+	}
+	return dropped
+}
+
+// buildResourceSubscription captures the resolved resource set, the raw wildcard patterns that
+// produced it, and the per-resource versions reported by the provider, so the next FetchDelta
+// run can decide what's still eligible for a delta sync.
+func buildResourceSubscription(info ProviderInfo, s *ProviderFetchSummary) *state.ResourceSubscription {
+	resources := make([]string, 0, len(s.FetchedResources))
+	versions := make(map[string]string, len(s.FetchedResources))
+	for name, rs := range s.FetchedResources {
+		resources = append(resources, name)
+		versions[name] = rs.Version
+	}
+	var wildcards []string
+	for _, r := range info.Config.Resources {
+		if strings.Contains(r, "*") {
+			wildcards = append(wildcards, r)
+		}
+	}
+	return &state.ResourceSubscription{
+		Provider:         info.Provider.Name,
+		Alias:            info.Config.Alias,
+		ProviderVersion:  s.Version,
+		Resources:        resources,
+		Wildcards:        wildcards,
+		ResourceVersions: versions,
+		UpdatedAt:        time.Now().UTC(),
+	}
+}
+
+func executeFetch(ctx context.Context, pLog logger.Logger, providerPlugin plugin.Plugin, info ProviderInfo, metadata map[string]interface{}, callback FetchUpdateCallback, storage database.Storage, fetchId uuid.UUID, sta *state.Client, mode FetchMode, knownVersions map[string]string, priorResources []string, completed map[string]bool, drainTimeout time.Duration) (*ProviderFetchSummary, diag.Diagnostics) {
 	var (
 		start   = time.Now()
 		summary = &ProviderFetchSummary{
@@ -351,26 +649,114 @@ func executeFetch(ctx context.Context, pLog zerolog.Logger, providerPlugin plugi
 		return summary, diags
 	}
 
-	pLog.Info().Msg("Provider started fetching resources")
-	stream, err := providerPlugin.Provider().FetchResources(ctx,
+	if mode == FetchDelta && len(priorResources) > 0 {
+		// A resource the prior subscription covered but this run's normalized list doesn't is one
+		// the user dropped entirely or narrowed out of a wildcard's scope; its previously fetched
+		// data is now orphaned and must be deleted, not just left stale, so it is honored the same
+		// way resp.RemovedIDs honors row-level removals below. Compared against the normalized
+		// list before resume filtering, since a resource only absent because this resumed run
+		// already completed it is not "dropped".
+		for _, dropped := range droppedResources(priorResources, resources) {
+			if err := storage.DeleteResources(ctx, dropped, nil); err != nil {
+				pLog.Warn("Failed to delete resource type dropped from scope", "resource", dropped, "error", err)
+				diags = diags.Add(diag.FromError(err, diag.INTERNAL, diag.WithResourceName(dropped)))
+				continue
+			}
+			pLog.Info("Deleted resource type no longer covered by configured resources", "resource", dropped)
+		}
+	}
+
+	if len(completed) > 0 {
+		remaining := make([]string, 0, len(resources))
+		for _, r := range resources {
+			if !completed[r] {
+				remaining = append(remaining, r)
+			}
+		}
+		resources = remaining
+	}
+
+	if len(resources) == 0 {
+		// Every requested resource was already checkpointed as complete; nothing left to do.
+		return summary, diags
+	}
+
+	pLog.Info("Provider started fetching resources", "mode", mode.String(), "resources", len(resources))
+	// drainCtx, not ctx, is what's actually handed to the provider call: ctx cancelation only
+	// ever triggers the transition into draining below, it never reaches the provider directly.
+	// That way a canceled ctx can't cut the stream off mid-flush; only drainTimeout elapsing (or
+	// the provider finishing on its own) can.
+	drainCtx, cancelDrain := context.WithCancel(context.Background())
+	defer cancelDrain()
+	stream, err := providerPlugin.Provider().FetchResources(drainCtx,
 		&cqproto.FetchResourcesRequest{
 			Resources:             resources,
 			ParallelFetchingLimit: info.Config.MaxParallelResourceFetchLimit,
 			MaxGoroutines:         info.Config.MaxGoroutines,
 			Timeout:               time.Duration(info.Config.ResourceTimeout) * time.Second,
 			Metadata:              metadata,
+			// KnownVersions tells the provider which resources it can treat as a delta base;
+			// only populated when mode is FetchDelta and a usable prior subscription exists.
+			KnownVersions: knownVersions,
 		})
 	if err != nil {
 		summary.Status = FetchFailed
 		return summary, diag.FromError(err, diag.INTERNAL)
 	}
 
+	var (
+		draining  int32
+		seenMu    sync.Mutex
+		seenNames = make(map[string]bool, len(resources))
+	)
+	// Watching ctx.Done() here, in its own goroutine, is what actually bounds a blocked
+	// stream.Recv() call: a non-blocking check between Recv calls only ever runs once the
+	// in-flight one returns, which defeats the point when ctx is canceled while Recv is already
+	// blocked waiting on the provider (the common case). drainCtx.Done() unblocks this goroutine
+	// once executeFetch returns normally, so it doesn't outlive the function waiting on a ctx that
+	// may never be canceled.
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-drainCtx.Done():
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&draining, 0, 1) {
+			return
+		}
+		seenMu.Lock()
+		remaining := len(resources) - len(seenNames)
+		seenMu.Unlock()
+		pLog.Warn("Fetch canceled, draining in-flight resources", "drain_timeout", drainTimeout, "remaining", remaining)
+		if callback != nil {
+			callback(FetchUpdate{
+				Name:          info.Provider.Name,
+				Alias:         info.Config.Alias,
+				Version:       providerPlugin.Version(),
+				Kind:          FetchUpdateDraining,
+				DrainingCount: remaining,
+			})
+		}
+		time.AfterFunc(drainTimeout, cancelDrain)
+	}()
+
 	for {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
 			// We didn't receive an error we received a response
-			pLog.Debug().Str("resource", resp.ResourceName).Uint64("fetched", resp.ResourceCount).Msg("Resource fetched successfully")
+			pLog.Debug("Resource fetched successfully", "resource", resp.ResourceName, "fetched", resp.ResourceCount, "unchanged", resp.Unchanged)
+			seenMu.Lock()
+			seenNames[resp.ResourceName] = true
+			seenMu.Unlock()
+			// Checkpoint this resource's completion before the callback fires: a crash between
+			// a row landing in storage and the checkpoint being written at worst re-fetches
+			// that one resource next time, it never causes one to be silently skipped.
+			if resp.FinishedResources[resp.ResourceName] {
+				if err := sta.SaveFetchCheckpoint(ctx, fetchId, info.Provider.Name, info.Config.Alias, resp.ResourceName, resp.Summary.ResourceCount, resp.Summary.Status.String()); err != nil {
+					pLog.Warn("Failed to persist fetch checkpoint", "resource", resp.ResourceName, "error", err)
+				}
+			}
 			if callback != nil {
 				callback(FetchUpdate{
 					Name:              info.Provider.Name,
@@ -380,30 +766,52 @@ func executeFetch(ctx context.Context, pLog zerolog.Logger, providerPlugin plugi
 					ResourceCount:     resp.ResourceCount,
 					DiagnosticCount:   diags.BySeverity(diag.WARNING, diag.ERROR, diag.PANIC).Len(),
 				})
-				// pLog.Debug().Str("resource", resp.ResourceName).Uint64("finishedCount", resp.ResourceCount).
-				//	Bool("finished", update.AllDone()).Int("finishCount", update.DoneCount()).Msg("received fetch update")
+			}
+			if mode == FetchDelta && resp.Unchanged {
+				// The provider confirmed this resource hasn't changed since KnownVersions; skip
+				// re-processing it but still record its (unchanged) version for the next delta.
+				summary.FetchedResources[resp.ResourceName] = ResourceFetchSummary{
+					Status:  resp.Summary.Status.String(),
+					Version: resp.ResourceVersion,
+				}
+				continue
+			}
+			if mode == FetchDelta && len(resp.RemovedIDs) > 0 {
+				if err := storage.DeleteResources(ctx, resp.ResourceName, resp.RemovedIDs); err != nil {
+					pLog.Warn("Failed to delete resources removed since the last delta fetch", "resource", resp.ResourceName, "error", err)
+					diags = diags.Add(diag.FromError(err, diag.INTERNAL, diag.WithResourceName(resp.ResourceName)))
+				}
 			}
 			summary.TotalResourcesFetched += resp.ResourceCount
 			events, rdiags := analytics.FilterTelemetryEvents(resp.Summary.Diagnostics)
 			summary.FetchedResources[resp.ResourceName] = ResourceFetchSummary{
-				resp.Summary.Status.String(),
-				resp.Summary.ResourceCount,
-				rdiags,
-				events,
-				time.Since(start),
+				Status:          resp.Summary.Status.String(),
+				ResourceCount:   resp.Summary.ResourceCount,
+				Diagnostics:     rdiags,
+				TelemetryEvents: events,
+				Duration:        time.Since(start),
+				Version:         resp.ResourceVersion,
 			}
 			if resp.Error != "" {
-				pLog.Warn().Err(err).Str("resource", resp.ResourceName).Msg("Received resource fetch error")
+				pLog.Warn("Received resource fetch error", "resource", resp.ResourceName, "error", resp.Error)
 				diags = diags.Add(diag.FromError(errors.New(resp.Error), diag.RESOLVING, diag.WithResourceName(resp.ResourceName)))
 			}
 			// TODO: print diags, specific to resource into log?
 			if rdiags.HasDiags() {
-				pLog.Warn().Str("resource", resp.ResourceName).Msg("Received resource fetch diagnostics")
+				pLog.Warn("Received resource fetch diagnostics", "resource", resp.ResourceName)
 				diags = diags.Add(rdiags)
 			}
 		case io.EOF:
 			// This case means the stream closed peacefully, i.e the provider finished without any error
-			pLog.Info().TimeDiff("execution", time.Now(), start).Msg("Provider finished fetch")
+			if atomic.LoadInt32(&draining) != 0 {
+				// The fetch was canceled, but every in-flight resource flushed before
+				// drainTimeout elapsed: still report it as canceled, since the caller asked to
+				// stop, even though nothing was lost.
+				pLog.Info("Provider finished draining before the drain timeout elapsed", "execution", time.Since(start))
+				summary.Status = FetchCanceled
+				return summary, diags
+			}
+			pLog.Info("Provider finished fetch", "execution", time.Since(start))
 			return summary, diags
 		default:
 			if callback != nil {
@@ -417,11 +825,11 @@ func executeFetch(ctx context.Context, pLog zerolog.Logger, providerPlugin plugi
 			}
 			// We received an error, first lets check if we got canceled, if not we log the error and add to diags
 			if cqerrors.IsCancelation(err) {
-				pLog.Warn().TimeDiff("execution", time.Now(), start).Msg("Provider fetch was canceled")
+				pLog.Warn("Provider fetch was canceled", "execution", time.Since(start))
 				summary.Status = FetchCanceled
 				return summary, diags.Add(cqerrors.CancelationDiag(err))
 			}
-			pLog.Error().Err(err).Msg("Received unexpected provider fetch error")
+			pLog.Error("Received unexpected provider fetch error", "error", err)
 			summary.Status = FetchFailed
 			return summary, diags.Add(diag.FromError(err, diag.INTERNAL))
 		}
@@ -558,6 +966,10 @@ func parseFetchedResources(resources map[string]ResourceFetchSummary) []state.Re
 			Status:        v.Status,
 			Error:         v.Diagnostics.Error(),
 			ResourceCount: v.ResourceCount,
+			// FetchedAt lets core/scheduler compute each resource's next due time from its TTL
+			// without reconstructing it from FetchSummary.Finish, which is per-provider not
+			// per-resource.
+			FetchedAt: time.Now().UTC(),
 		})
 	}
 	return rfs
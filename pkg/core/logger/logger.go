@@ -0,0 +1,20 @@
+// Package logger defines the structured logging interface that pkg/core's entrypoints (Fetch,
+// the scheduler, ...) depend on, along with adapters so embedders can route CloudQuery's logs
+// into their own logging stack instead of the package-global zerolog logger every entrypoint
+// used to reach for directly.
+package logger
+
+// Logger is the structured logging interface pkg/core depends on. Each level takes a message
+// followed by alternating key/value pairs, the same convention zap's SugaredLogger and the
+// standard library's log/slog use, so all three fit behind it without an adapter having to
+// reshape its caller's fields.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+	// With returns a Logger that prepends kv to every subsequent call, mirroring
+	// zerolog.Context/zap.Logger.With: it's how pkg/core derives a per-provider logger without
+	// threading the same fields through every call site by hand.
+	With(kv ...interface{}) Logger
+}
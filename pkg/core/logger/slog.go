@@ -0,0 +1,23 @@
+package logger
+
+import "log/slog"
+
+// slogAdapter adapts a *slog.Logger to Logger, for embedders standardizing on the standard
+// library's structured logging package.
+type slogAdapter struct {
+	l *slog.Logger
+}
+
+// NewSlog wraps a *slog.Logger as a Logger.
+func NewSlog(l *slog.Logger) Logger {
+	return slogAdapter{l: l}
+}
+
+func (s slogAdapter) Debug(msg string, kv ...interface{}) { s.l.Debug(msg, kv...) }
+func (s slogAdapter) Info(msg string, kv ...interface{})  { s.l.Info(msg, kv...) }
+func (s slogAdapter) Warn(msg string, kv ...interface{})  { s.l.Warn(msg, kv...) }
+func (s slogAdapter) Error(msg string, kv ...interface{}) { s.l.Error(msg, kv...) }
+
+func (s slogAdapter) With(kv ...interface{}) Logger {
+	return slogAdapter{l: s.l.With(kv...)}
+}
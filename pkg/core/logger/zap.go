@@ -0,0 +1,22 @@
+package logger
+
+import "go.uber.org/zap"
+
+// zapAdapter adapts a *zap.SugaredLogger to Logger, for embedders who already run zap.
+type zapAdapter struct {
+	l *zap.SugaredLogger
+}
+
+// NewZap wraps a *zap.Logger as a Logger.
+func NewZap(l *zap.Logger) Logger {
+	return zapAdapter{l: l.Sugar()}
+}
+
+func (z zapAdapter) Debug(msg string, kv ...interface{}) { z.l.Debugw(msg, kv...) }
+func (z zapAdapter) Info(msg string, kv ...interface{})  { z.l.Infow(msg, kv...) }
+func (z zapAdapter) Warn(msg string, kv ...interface{})  { z.l.Warnw(msg, kv...) }
+func (z zapAdapter) Error(msg string, kv ...interface{}) { z.l.Errorw(msg, kv...) }
+
+func (z zapAdapter) With(kv ...interface{}) Logger {
+	return zapAdapter{l: z.l.With(kv...)}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// zerologAdapter adapts a zerolog.Logger to Logger. It's the default pkg/core falls back to
+// when no Logger is supplied, so embedders that don't care keep today's behavior unchanged.
+type zerologAdapter struct {
+	l zerolog.Logger
+}
+
+// NewZerolog wraps an existing zerolog.Logger as a Logger.
+func NewZerolog(l zerolog.Logger) Logger {
+	return zerologAdapter{l: l}
+}
+
+// Default returns a Logger backed by the package-global zerolog logger (as used throughout the
+// rest of this codebase), for callers that don't configure one explicitly.
+func Default() Logger {
+	return NewZerolog(log.Logger)
+}
+
+func (z zerologAdapter) Debug(msg string, kv ...interface{}) { writeEvent(z.l.Debug(), msg, kv) }
+func (z zerologAdapter) Info(msg string, kv ...interface{})  { writeEvent(z.l.Info(), msg, kv) }
+func (z zerologAdapter) Warn(msg string, kv ...interface{})  { writeEvent(z.l.Warn(), msg, kv) }
+func (z zerologAdapter) Error(msg string, kv ...interface{}) { writeEvent(z.l.Error(), msg, kv) }
+
+func (z zerologAdapter) With(kv ...interface{}) Logger {
+	ctx := z.l.With()
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, _ := kv[i].(string)
+		ctx = ctx.Interface(key, kv[i+1])
+	}
+	return zerologAdapter{l: ctx.Logger()}
+}
+
+func writeEvent(e *zerolog.Event, msg string, kv []interface{}) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, _ := kv[i].(string)
+		if err, ok := kv[i+1].(error); ok {
+			e = e.AnErr(key, err)
+			continue
+		}
+		e = e.Interface(key, kv[i+1])
+	}
+	e.Msg(msg)
+}
@@ -0,0 +1,216 @@
+// Package scheduler turns core.Fetch from a one-shot call into a supervised, periodic worker,
+// with an independent tick interval per provider and optional per-resource TTL overrides so
+// cheap resources can refresh more often than expensive ones.
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cloudquery/cloudquery/pkg/core"
+	"github.com/cloudquery/cloudquery/pkg/core/database"
+	"github.com/cloudquery/cloudquery/pkg/core/logger"
+	"github.com/cloudquery/cloudquery/pkg/core/state"
+	"github.com/cloudquery/cloudquery/pkg/plugin"
+)
+
+// ProviderSchedule configures how often a single provider (or a single aliased instance of it)
+// is fetched.
+type ProviderSchedule struct {
+	Provider core.ProviderInfo
+	// Every is the default interval between ticks for this provider, used for any resource that
+	// doesn't have a more specific entry in ResourceTTLs.
+	Every time.Duration
+	// ResourceTTLs overrides Every for specific resources, keyed by resource name (e.g.
+	// "aws.ec2.instances": 5*time.Minute, "aws.s3.buckets": 24*time.Hour). A resource whose TTL
+	// hasn't elapsed since it was last fetched is left out of that tick's request; a fetch of it
+	// that failed counts as well.
+	ResourceTTLs map[string]time.Duration
+}
+
+func (p ProviderSchedule) key() string {
+	return p.Provider.Config.Name + "@" + p.Provider.Config.Alias
+}
+
+// Options configures a Scheduler.
+type Options struct {
+	Storage database.Storage
+	Plugins *plugin.Manager
+	State   *state.Client
+	// MaxConcurrentFetches caps how many providers can be fetching at once across the whole
+	// scheduler, independent of how many providers are scheduled. Defaults to one per provider.
+	MaxConcurrentFetches int
+	// Jitter adds up to this much random delay on top of each tick interval, so that providers
+	// restarted together don't all tick in lockstep.
+	Jitter time.Duration
+	// UpdateCallback is forwarded to core.Fetch on every tick, the same as a one-shot fetch.
+	UpdateCallback core.FetchUpdateCallback
+	// Logger receives the scheduler's own log output and is forwarded to core.Fetch on every
+	// tick. Defaults to a zerolog-backed adapter, matching core.FetchOptions.Logger's default.
+	Logger logger.Logger
+}
+
+// Scheduler runs one supervised tick loop per configured provider until its context is
+// canceled, computing at each tick which resources are due and fetching only those.
+type Scheduler struct {
+	opts      Options
+	schedules []ProviderSchedule
+	sem       chan struct{}
+
+	mu      sync.Mutex
+	running map[string]bool
+	trigger map[string]chan struct{}
+}
+
+// New builds a Scheduler for the given provider schedules. Call Run to start it.
+func New(opts Options, schedules []ProviderSchedule) *Scheduler {
+	if opts.MaxConcurrentFetches <= 0 {
+		opts.MaxConcurrentFetches = len(schedules)
+	}
+	if opts.Logger == nil {
+		opts.Logger = logger.Default()
+	}
+	s := &Scheduler{
+		opts:      opts,
+		schedules: schedules,
+		sem:       make(chan struct{}, opts.MaxConcurrentFetches),
+		running:   make(map[string]bool, len(schedules)),
+		trigger:   make(map[string]chan struct{}, len(schedules)),
+	}
+	for _, sch := range schedules {
+		s.trigger[sch.key()] = make(chan struct{}, 1)
+	}
+	return s
+}
+
+// Run owns one goroutine per scheduled provider and blocks until ctx is canceled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, sch := range s.schedules {
+		wg.Add(1)
+		go func(sch ProviderSchedule) {
+			defer wg.Done()
+			s.runProviderLoop(ctx, sch)
+		}(sch)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// TriggerNow asks the scheduler to run the given provider's (identified by "name@alias") next
+// tick immediately instead of waiting for its interval to elapse. It's a no-op if a trigger is
+// already pending for that provider, and if the key is unknown. A trigger sent while a tick is
+// in flight is kept, and runs the next tick as soon as the current one finishes.
+func (s *Scheduler) TriggerNow(providerKey string) {
+	s.mu.Lock()
+	ch, ok := s.trigger[providerKey]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) runProviderLoop(ctx context.Context, sch ProviderSchedule) {
+	key := sch.key()
+	timer := time.NewTimer(s.jitter(sch.Every))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.trigger[key]:
+		case <-timer.C:
+		}
+		s.tick(ctx, sch)
+		timer.Reset(s.jitter(sch.Every))
+	}
+}
+
+func (s *Scheduler) jitter(every time.Duration) time.Duration {
+	if s.opts.Jitter <= 0 {
+		return every
+	}
+	return every + time.Duration(rand.Int63n(int64(s.opts.Jitter)))
+}
+
+// tick runs a single scheduled fetch for sch, skipping it entirely if the previous tick for the
+// same provider is still executing.
+func (s *Scheduler) tick(ctx context.Context, sch ProviderSchedule) {
+	key := sch.key()
+
+	s.mu.Lock()
+	if s.running[key] {
+		s.mu.Unlock()
+		s.opts.Logger.Debug("Skipping scheduled tick, previous run still executing", "provider", key)
+		return
+	}
+	s.running[key] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running[key] = false
+		s.mu.Unlock()
+	}()
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return
+	}
+
+	due, err := s.dueResources(ctx, sch)
+	if err != nil {
+		s.opts.Logger.Error("Failed to compute due resources for scheduled tick", "provider", key, "error", err)
+		return
+	}
+	if len(due) == 0 {
+		s.opts.Logger.Debug("No resources due this tick", "provider", key)
+		return
+	}
+
+	info := sch.Provider
+	cfg := *info.Config
+	cfg.Resources = due
+	info.Config = &cfg
+
+	_, diags := core.Fetch(ctx, s.opts.State, s.opts.Storage, s.opts.Plugins, &core.FetchOptions{
+		UpdateCallback: s.opts.UpdateCallback,
+		ProvidersInfo:  []core.ProviderInfo{info},
+		Logger:         s.opts.Logger,
+	})
+	if diags.HasErrors() {
+		s.opts.Logger.Error("Scheduled fetch completed with errors", "provider", key)
+	}
+}
+
+// dueResources filters a provider's configured resources down to the ones whose TTL (or the
+// provider-level Every default) has elapsed since they were last fetched, using the
+// per-resource "last fetched at" timestamps persisted in state.Client. Those timestamps are
+// recorded whatever the resource's fetch status, so a failed fetch also resets its TTL.
+func (s *Scheduler) dueResources(ctx context.Context, sch ProviderSchedule) ([]string, error) {
+	lastFetched, err := s.opts.State.GetResourceFetchTimes(ctx, sch.Provider.Config.Name, sch.Provider.Config.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := make([]string, 0, len(sch.Provider.Config.Resources))
+	for _, r := range sch.Provider.Config.Resources {
+		ttl := sch.Every
+		if t, ok := sch.ResourceTTLs[r]; ok {
+			ttl = t
+		}
+		last, ok := lastFetched[r]
+		if !ok || now.Sub(last) >= ttl {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckpointResourceSummary is the per-resource portion of a FetchCheckpoint. It intentionally
+// mirrors the persisted fields of core.ResourceFetchSummary rather than importing pkg/core:
+// pkg/core imports pkg/core/state, so the reverse would be an import cycle. A caller resuming a
+// fetch is responsible for converting this back into its own summary type.
+type CheckpointResourceSummary struct {
+	Status        string
+	ResourceCount uint64
+}
+
+// FetchCheckpoint records a fetch's progress partway through, keyed by FetchId, so a process
+// restart can resume it instead of refetching every resource from scratch.
+type FetchCheckpoint struct {
+	FetchId            uuid.UUID
+	ProviderName       string
+	ProviderAlias      string
+	UpdatedAt          time.Time
+	CompletedResources map[string]bool
+	Resources          map[string]CheckpointResourceSummary
+}
+
+// GetFetchCheckpoint returns the checkpoint recorded for fetchId/providerName/alias, or nil if
+// this fetch never checkpointed any progress (e.g. it's brand new). Checkpoints are not cleared
+// when a fetch finishes, so a fetch that completed still has one covering every resource.
+func (c *Client) GetFetchCheckpoint(ctx context.Context, fetchId uuid.UUID, providerName, alias string) (*FetchCheckpoint, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT resource_name, status, resource_count, updated_at
+		FROM fetch_checkpoints
+		WHERE fetch_id = $1 AND provider_name = $2 AND provider_alias = $3
+	`, fetchId, providerName, alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fetch checkpoint: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoint := &FetchCheckpoint{
+		FetchId:            fetchId,
+		ProviderName:       providerName,
+		ProviderAlias:      alias,
+		CompletedResources: make(map[string]bool),
+		Resources:          make(map[string]CheckpointResourceSummary),
+	}
+	found := false
+	for rows.Next() {
+		found = true
+		var (
+			name   string
+			status string
+			count  uint64
+			at     time.Time
+		)
+		if err := rows.Scan(&name, &status, &count, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan fetch checkpoint: %w", err)
+		}
+		checkpoint.Resources[name] = CheckpointResourceSummary{Status: status, ResourceCount: count}
+		checkpoint.CompletedResources[name] = true
+		if at.After(checkpoint.UpdatedAt) {
+			checkpoint.UpdatedAt = at
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return checkpoint, nil
+}
+
+// SaveFetchCheckpoint records a single resource's completion within fetchId's checkpoint.
+func (c *Client) SaveFetchCheckpoint(ctx context.Context, fetchId uuid.UUID, providerName, alias, resourceName string, resourceCount uint64, status string) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO fetch_checkpoints (fetch_id, provider_name, provider_alias, resource_name, status, resource_count, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now())
+		ON CONFLICT (fetch_id, provider_name, provider_alias, resource_name)
+		DO UPDATE SET status = $5, resource_count = $6, updated_at = now()
+	`, fetchId, providerName, alias, resourceName, status, resourceCount)
+	if err != nil {
+		return fmt.Errorf("failed to save fetch checkpoint for resource %s: %w", resourceName, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FetchSummary is a persisted record of a single provider's fetch run, written once at the end of
+// every Fetch (successful, partial, or canceled) so fetch history survives process restarts.
+type FetchSummary struct {
+	FetchId            uuid.UUID
+	CreatedAt          time.Time
+	Start              time.Time
+	Finish             time.Time
+	IsSuccess          bool
+	TotalResourceCount uint64
+	TotalErrorsCount   int
+	ProviderName       string
+	ProviderAlias      string
+	ProviderVersion    string
+	CoreVersion        string
+	Resources          []ResourceFetchSummary
+}
+
+// ResourceFetchSummary is the per-resource portion of a FetchSummary.
+type ResourceFetchSummary struct {
+	ResourceName  string
+	Status        string
+	Error         string
+	ResourceCount uint64
+	// FetchedAt is when this resource last finished fetching. core/scheduler uses it (via
+	// GetResourceFetchTimes) to compute each resource's next due time from its TTL.
+	FetchedAt time.Time
+}
+
+// SaveFetchSummary persists fs and its per-resource summaries in a single transaction.
+func (c *Client) SaveFetchSummary(ctx context.Context, fs *FetchSummary) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin fetch summary transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO fetch_summaries
+			(fetch_id, created_at, start, finish, is_success, total_resource_count, total_errors_count, provider_name, provider_alias, provider_version, core_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (fetch_id, provider_name, provider_alias)
+		DO UPDATE SET created_at = $2, start = $3, finish = $4, is_success = $5, total_resource_count = $6, total_errors_count = $7, provider_version = $10, core_version = $11
+	`, fs.FetchId, fs.CreatedAt, fs.Start, fs.Finish, fs.IsSuccess, fs.TotalResourceCount, fs.TotalErrorsCount, fs.ProviderName, fs.ProviderAlias, fs.ProviderVersion, fs.CoreVersion); err != nil {
+		return fmt.Errorf("failed to insert fetch summary: %w", err)
+	}
+
+	for _, r := range fs.Resources {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO resource_fetch_summaries
+				(fetch_id, provider_name, provider_alias, resource_name, status, error, resource_count, fetched_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (fetch_id, provider_name, provider_alias, resource_name)
+			DO UPDATE SET status = $5, error = $6, resource_count = $7, fetched_at = $8
+		`, fs.FetchId, fs.ProviderName, fs.ProviderAlias, r.ResourceName, r.Status, r.Error, r.ResourceCount, r.FetchedAt); err != nil {
+			return fmt.Errorf("failed to insert resource fetch summary for %s: %w", r.ResourceName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fetch summary: %w", err)
+	}
+	return nil
+}
+
+// GetResourceFetchTimes returns the most recent FetchedAt for every resource of the given
+// provider instance, keyed by resource name. core/scheduler uses this to decide which resources
+// are due for their next tick.
+func (c *Client) GetResourceFetchTimes(ctx context.Context, providerName, alias string) (map[string]time.Time, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT resource_name, MAX(fetched_at)
+		FROM resource_fetch_summaries
+		WHERE provider_name = $1 AND provider_alias = $2
+		GROUP BY resource_name
+	`, providerName, alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resource fetch times: %w", err)
+	}
+	defer rows.Close()
+
+	times := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			name string
+			at   time.Time
+		)
+		if err := rows.Scan(&name, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan resource fetch time: %w", err)
+		}
+		times[name] = at
+	}
+	return times, rows.Err()
+}
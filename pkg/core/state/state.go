@@ -0,0 +1,92 @@
+// Package state persists fetch history, resource subscriptions, and in-flight fetch checkpoints
+// to Postgres, so this bookkeeping survives process restarts the same way the fetched data
+// itself does. It's a separate package (rather than living in pkg/core) so that pkg/core/state's
+// types stay free of pkg/core types: pkg/core imports pkg/core/state, and Go doesn't allow the
+// reverse.
+package state
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// schemaSQL creates every table this package reads and writes, idempotently, so New can be
+// called against a fresh database with no separate migration step.
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS fetch_summaries (
+	fetch_id uuid NOT NULL,
+	created_at timestamptz NOT NULL,
+	start timestamptz NOT NULL,
+	finish timestamptz NOT NULL,
+	is_success boolean NOT NULL,
+	total_resource_count bigint NOT NULL,
+	total_errors_count int NOT NULL,
+	provider_name text NOT NULL,
+	provider_alias text NOT NULL,
+	provider_version text NOT NULL,
+	core_version text NOT NULL,
+	PRIMARY KEY (fetch_id, provider_name, provider_alias)
+);
+
+CREATE TABLE IF NOT EXISTS resource_fetch_summaries (
+	fetch_id uuid NOT NULL,
+	provider_name text NOT NULL,
+	provider_alias text NOT NULL,
+	resource_name text NOT NULL,
+	status text NOT NULL,
+	error text NOT NULL DEFAULT '',
+	resource_count bigint NOT NULL,
+	fetched_at timestamptz NOT NULL,
+	PRIMARY KEY (fetch_id, provider_name, provider_alias, resource_name)
+);
+
+CREATE TABLE IF NOT EXISTS resource_subscriptions (
+	provider_name text NOT NULL,
+	provider_alias text NOT NULL,
+	provider_version text NOT NULL,
+	resources jsonb NOT NULL,
+	wildcards jsonb NOT NULL,
+	resource_versions jsonb NOT NULL,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (provider_name, provider_alias, provider_version)
+);
+
+CREATE TABLE IF NOT EXISTS fetch_checkpoints (
+	fetch_id uuid NOT NULL,
+	provider_name text NOT NULL,
+	provider_alias text NOT NULL,
+	resource_name text NOT NULL,
+	status text NOT NULL,
+	resource_count bigint NOT NULL,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (fetch_id, provider_name, provider_alias, resource_name)
+);
+`
+
+// Client stores and retrieves fetch state in Postgres. It's safe for concurrent use.
+type Client struct {
+	db *sql.DB
+}
+
+// New opens a Client against dsn (the same connection string used for the fetched data itself)
+// and ensures its backing tables exist.
+func New(ctx context.Context, dsn string) (*Client, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	c := &Client{db: db}
+	if _, err := db.ExecContext(ctx, schemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate state database: %w", err)
+	}
+	return c, nil
+}
+
+// Close releases the underlying database connection.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ResourceSubscription records, per provider instance and provider version, which resources (and
+// wildcard patterns) the last successful FetchDelta run covered, and the version each resource
+// reported, so the next delta fetch knows what it can still treat as a delta base.
+type ResourceSubscription struct {
+	Provider         string
+	Alias            string
+	ProviderVersion  string
+	Resources        []string
+	Wildcards        []string
+	ResourceVersions map[string]string
+	UpdatedAt        time.Time
+}
+
+// GetResourceSubscription returns the subscription recorded for the given provider instance and
+// version, or nil if none exists yet (e.g. this is its first delta fetch, or the provider was
+// upgraded since the last one).
+func (c *Client) GetResourceSubscription(ctx context.Context, providerName, alias, providerVersion string) (*ResourceSubscription, error) {
+	var (
+		resourcesJSON, wildcardsJSON, versionsJSON []byte
+		sub                                        ResourceSubscription
+	)
+	err := c.db.QueryRowContext(ctx, `
+		SELECT resources, wildcards, resource_versions, updated_at
+		FROM resource_subscriptions
+		WHERE provider_name = $1 AND provider_alias = $2 AND provider_version = $3
+	`, providerName, alias, providerVersion).Scan(&resourcesJSON, &wildcardsJSON, &versionsJSON, &sub.UpdatedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to query resource subscription: %w", err)
+	}
+
+	if err := json.Unmarshal(resourcesJSON, &sub.Resources); err != nil {
+		return nil, fmt.Errorf("failed to decode subscribed resources: %w", err)
+	}
+	if err := json.Unmarshal(wildcardsJSON, &sub.Wildcards); err != nil {
+		return nil, fmt.Errorf("failed to decode subscribed wildcards: %w", err)
+	}
+	if err := json.Unmarshal(versionsJSON, &sub.ResourceVersions); err != nil {
+		return nil, fmt.Errorf("failed to decode subscribed resource versions: %w", err)
+	}
+	sub.Provider, sub.Alias, sub.ProviderVersion = providerName, alias, providerVersion
+	return &sub, nil
+}
+
+// SaveResourceSubscription upserts sub, replacing whatever was previously recorded for the same
+// provider instance and version.
+func (c *Client) SaveResourceSubscription(ctx context.Context, sub *ResourceSubscription) error {
+	resourcesJSON, err := json.Marshal(sub.Resources)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscribed resources: %w", err)
+	}
+	wildcardsJSON, err := json.Marshal(sub.Wildcards)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscribed wildcards: %w", err)
+	}
+	versionsJSON, err := json.Marshal(sub.ResourceVersions)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscribed resource versions: %w", err)
+	}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO resource_subscriptions (provider_name, provider_alias, provider_version, resources, wildcards, resource_versions, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (provider_name, provider_alias, provider_version)
+		DO UPDATE SET resources = $4, wildcards = $5, resource_versions = $6, updated_at = $7
+	`, sub.Provider, sub.Alias, sub.ProviderVersion, resourcesJSON, wildcardsJSON, versionsJSON, sub.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save resource subscription: %w", err)
+	}
+	return nil
+}